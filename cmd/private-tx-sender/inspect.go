@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/config"
+)
+
+const pingTimeout = 3 * time.Second
+
+// bundleFlags collects the --bundle builderid=bundleid flags of status, the builder id is the one printed by send.
+type bundleFlags map[string]builder.BundleID
+
+func (f bundleFlags) String() string {
+	return fmt.Sprint(map[string]builder.BundleID(f))
+}
+
+func (f bundleFlags) Set(value string) error {
+	builderID, id, ok := strings.Cut(value, "=")
+	if !ok || builderID == "" || id == "" {
+		return fmt.Errorf("want builderid=bundleid, got %q", value)
+	}
+
+	f[builderID] = builder.BundleID(id)
+	return nil
+}
+
+func runStatus(ctx context.Context, args []string) error {
+	bundles := bundleFlags{}
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	fs.Var(bundles, "bundle", "Give the builderid=bundleid printed by send to query the builder, can be repeated")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: status [--bundle builderid=bundleid]... <txhash>")
+	}
+
+	hash, err := hexutil.Decode(fs.Arg(0))
+	if err != nil || len(hash) != common.HashLength {
+		return fmt.Errorf("invalid tx hash: %q", fs.Arg(0))
+	}
+	txHash := common.BytesToHash(hash)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := ethclient.DialContext(ctx, cfg.Sender.ChainURL)
+	if err != nil {
+		return err
+	}
+
+	if err := printBundleStatus(ctx, cfg, bundles); err != nil {
+		return err
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if err == nil {
+		fmt.Println("state: included", "block:", receipt.BlockNumber, "status:", receipt.Status, "gasUsed:", receipt.GasUsed)
+		return nil
+	}
+
+	if !errors.Is(err, ethereum.NotFound) {
+		return err
+	}
+
+	_, isPending, err := client.TransactionByHash(ctx, txHash)
+	switch {
+	case err == nil && isPending:
+		fmt.Println("state: pending in the public mempool")
+	case errors.Is(err, ethereum.NotFound):
+		fmt.Println("state: not found, private txs stay invisible to public nodes until included")
+	default:
+		return err
+	}
+
+	return nil
+}
+
+// printBundleStatus asks the builders of the bundles for their status.
+func printBundleStatus(ctx context.Context, cfg *config.Config, bundles bundleFlags) error {
+	if len(bundles) == 0 {
+		return nil
+	}
+
+	builders, err := builder.NewAll(cfg.Builders)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]builder.Builder, len(builders))
+	for _, b := range builders {
+		byID[builder.ID(b)] = b
+	}
+
+	ids := make([]string, 0, len(bundles))
+	for id := range bundles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		b, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("builder %s not in the config, it was changed since the bundle was sent", id)
+		}
+
+		querier, ok := b.(builder.BundleStatusQuerier)
+		if !ok {
+			fmt.Println("builder:", id, "bundle status not reported by the builder")
+			continue
+		}
+
+		status, err := querier.BundleStatus(ctx, bundles[id])
+		if err != nil {
+			fmt.Println("builder:", id, "error:", err)
+			continue
+		}
+
+		fmt.Println("builder:", id, "bundle:", status.State, "block:", status.BlockNumber, "reason:", status.Reason)
+	}
+
+	return nil
+}
+
+func runBuildersPing(ctx context.Context) error {
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	failedBuilders, failedEndpoints, endpoints := 0, 0, 0
+	for _, bc := range cfg.Builders {
+		// every regional endpoint is pinged on its own, the builder is unreachable when none answers
+		reachable := false
+		for _, url := range bc.Endpoints() {
+			endpoints++
+			endpoint := bc
+			endpoint.URL, endpoint.URLs = url, nil
+
+			pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+			latency, err := builder.Ping(pingCtx, endpoint)
+			cancel()
+
+			if err != nil {
+				failedEndpoints++
+				fmt.Printf("%-12s %-50s unreachable: %v\n", bc.Brand, url, err)
+				continue
+			}
+
+			reachable = true
+			fmt.Printf("%-12s %-50s %v\n", bc.Brand, url, latency.Round(time.Millisecond))
+		}
+
+		if !reachable {
+			failedBuilders++
+		}
+	}
+
+	if failedEndpoints > 0 {
+		return fmt.Errorf("%d of %d builders unreachable, %d of %d endpoints unreachable",
+			failedBuilders, len(cfg.Builders), failedEndpoints, endpoints)
+	}
+
+	return nil
+}
+
+func runConfigCheck(ctx context.Context) error {
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := ethclient.DialContext(ctx, cfg.Sender.ChainURL)
+	if err != nil {
+		return fmt.Errorf("Sender.ChainURL: %w", err)
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("Sender.ChainURL: %w", err)
+	}
+
+	if profile, ok := cfg.Chain.Profile(); ok && chainID.Uint64() != profile.ChainID {
+		return fmt.Errorf("Sender.ChainURL: chain id %d does not match chain %s", chainID, cfg.Chain)
+	}
+
+	fmt.Println("config ok, chain:", cfg.Chain, "chain id:", chainID, "builders:", len(cfg.Builders))
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Usage: private-tx-sender [--config config.toml] <command> [args]
+
+Commands:
+  send            send a raw tx, or sign one with a keystore, through the builders
+  status <hash>   show the inclusion status of a tx, --bundle index=id also asks the builder
+  builders ping   check the reachability and latency of the configured builders
+  config check    validate the config file
+`
+
+var configPath = flag.String("config", "./config.toml", "Give a config file path")
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch {
+	case args[0] == "send":
+		err = runSend(ctx, args[1:])
+	case args[0] == "status":
+		err = runStatus(ctx, args[1:])
+	case args[0] == "builders" && len(args) > 1 && args[1] == "ping":
+		err = runBuildersPing(ctx)
+	case args[0] == "config" && len(args) > 1 && args[1] == "check":
+		err = runConfigCheck(ctx)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/config"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func runSend(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("send", flag.ExitOnError)
+	raw := fs.String("raw", "", "Give a signed raw tx in hex")
+	keystorePath := fs.String("keystore", "", "Give a keystore file to sign the tx with")
+	passwordFile := fs.String("password-file", "", "Give a file holding the keystore password")
+	to := fs.String("to", "", "Give the recipient of the signed tx")
+	value := fs.String("value", "0", "Give the value of the signed tx in wei")
+	data := fs.String("data", "", "Give the calldata of the signed tx in hex")
+	gas := fs.Uint64("gas", 0, "Give the gas limit of the signed tx, estimated if zero")
+	revertible := fs.Bool("revertible", false, "Allow the tx to be included even if it reverts")
+	profile := fs.String("profile", "", "Give the profile to send with")
+	wait := fs.Bool("wait", false, "Wait until the tx is included or its bundle window passes")
+	_ = fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := ethclient.DialContext(ctx, cfg.Sender.ChainURL)
+	if err != nil {
+		return err
+	}
+
+	var input hexutil.Bytes
+	switch {
+	case *raw != "":
+		input, err = hexutil.Decode(*raw)
+	case *keystorePath != "":
+		input, err = signTx(ctx, client, *keystorePath, *passwordFile, *to, *value, *data, *gas)
+	default:
+		err = errors.New("either --raw or --keystore is required")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	builders, err := builder.NewAll(cfg.Builders)
+	if err != nil {
+		return err
+	}
+
+	txSender := txsender.NewPrivateTxSender(ctx, cfg.Sender, builders)
+	if txSender == nil {
+		return errors.New("failed to create private tx sender")
+	}
+
+	result, err := txSender.SendRawTransaction(ctx, input, *revertible, txsender.WithProfile(*profile))
+	if err != nil {
+		return err
+	}
+
+	// the other builders are still being sent to, exiting now would cancel them
+	if err := txSender.Drain(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("txHash:", result.TxHash.Hex())
+	if status, ok := txSender.TxStatus(result.TxHash); ok && len(status.Bundles) > 0 {
+		for _, bundle := range status.Bundles {
+			fmt.Println("builder:", bundle.ID, bundle.Brand, "bundleID:", bundle.BundleID)
+		}
+	} else {
+		fmt.Println("builder:", result.ID, result.Brand, "bundleID:", result.BundleID)
+	}
+
+	if !*wait {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(cfg.Sender.BlockInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			status, ok := txSender.TxStatus(result.TxHash)
+			if !ok || !status.State.Finished() {
+				continue
+			}
+
+			fmt.Println("state:", status.State, "block:", status.BlockNumber, "reason:", status.Reason)
+			return nil
+		}
+	}
+}
+
+func signTx(ctx context.Context, client *ethclient.Client, keystorePath, passwordFile, to, value, data string, gas uint64) (hexutil.Bytes, error) {
+	keyJSON, err := os.ReadFile(keystorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	password := ""
+	if passwordFile != "" {
+		b, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, err
+		}
+
+		password = strings.TrimRight(string(b), "\r\n")
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if !common.IsHexAddress(to) {
+		return nil, fmt.Errorf("invalid --to address: %q", to)
+	}
+	toAddr := common.HexToAddress(to)
+
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid --value: %q", value)
+	}
+
+	var calldata []byte
+	if data != "" {
+		if calldata, err = hexutil.Decode(data); err != nil {
+			return nil, err
+		}
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, key.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if gas == 0 {
+		gas, err = client.EstimateGas(ctx, ethereum.CallMsg{From: key.Address, To: &toAddr, Value: amount, Data: calldata})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &toAddr,
+		Value:    amount,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     calldata,
+	})
+
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return signedTx.MarshalBinary()
+}
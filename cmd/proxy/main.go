@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/config"
+	"github.com/node-real/private-tx-sender/pkg/proxy"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+var configPath = flag.String("config", "./config.toml", "Give a config file path")
+
+func main() {
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Crit("failed to load config", "err", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	builders, err := builder.NewAll(cfg.Builders)
+	if err != nil {
+		log.Crit("failed to create builders", "err", err)
+	}
+
+	txSender := txsender.NewPrivateTxSender(ctx, cfg.Sender, builders)
+	if txSender == nil {
+		log.Crit("failed to create private tx sender")
+	}
+
+	warmCtx, warmCancel := context.WithCancel(ctx)
+	builder.KeepWarm(warmCtx, builders)
+
+	// only the top level builders are hot reloaded, tenants, their api keys and builders and the other
+	// settings still need a restart
+	go config.Watch(ctx, *configPath, func(newCfg *config.Config) {
+		diff := config.DiffBuilders(cfg.Builders, newCfg.Builders)
+		if diff.Empty() {
+			log.Info("builders unchanged")
+			return
+		}
+
+		reloaded, err := reloadBuilders(builders, newCfg.Builders, diff)
+		if err != nil {
+			log.Error("failed to reload builders, keep the current ones", "err", err)
+			return
+		}
+
+		diff.Log()
+		txSender.SetBuilders(ctx, reloaded)
+
+		warmCancel()
+		warmCtx, warmCancel = context.WithCancel(ctx)
+		builder.KeepWarm(warmCtx, reloaded)
+		cfg.Builders, builders = newCfg.Builders, reloaded
+	})
+
+	server, err := proxy.New(cfg.Proxy, cfg.Sender.ChainURL, txSender)
+	if err != nil {
+		log.Crit("failed to create proxy", "err", err)
+	}
+
+	if err := server.Run(ctx); err != nil {
+		log.Crit("proxy server exited", "err", err)
+	}
+}
+
+// reloadBuilders reuses the current builders whose config did not change, so their connections and the
+// bundles tracked on them are kept, and creates the others.
+func reloadBuilders(current []builder.Builder, configs []builder.Config, diff config.BuildersDiff) ([]builder.Builder, error) {
+	reloaded := make([]builder.Builder, len(configs))
+	for _, i := range diff.Kept {
+		reloaded[i] = current[i]
+	}
+
+	changed := make([]builder.Config, 0, len(configs)-len(diff.Kept))
+	for i, bc := range configs {
+		if reloaded[i] == nil {
+			changed = append(changed, bc)
+		}
+	}
+
+	created, err := builder.NewAll(changed)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reloaded {
+		if reloaded[i] == nil {
+			reloaded[i], created = created[0], created[1:]
+		}
+	}
+
+	return reloaded, nil
+}
@@ -7,7 +7,6 @@ import (
 	"math/big"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/config"
 	"github.com/node-real/private-tx-sender/pkg/txsender"
 )
 
@@ -23,15 +23,13 @@ var (
 	privatekey = flag.String("privatekey", "", "Give a private key")
 )
 
-type Config struct {
-	Sender   txsender.Config
-	Builders []builder.Config
-}
-
 func main() {
 	flag.Parse()
 
-	cfg := LoadConfig(*configPath)
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		panic(err)
+	}
 
 	ctx, _ := context.WithCancel(context.Background())
 
@@ -40,20 +38,22 @@ func main() {
 		panic("failed to dial chain")
 	}
 
-	builders := make([]builder.Builder, 0)
-	for _, v := range cfg.Builders {
-		builders = append(builders, builder.New(v))
+	builders, err := builder.NewAll(cfg.Builders)
+	if err != nil {
+		panic(err)
 	}
 
 	txSender := txsender.NewPrivateTxSender(ctx, cfg.Sender, builders)
 
 	txHash, txByte := generateTx(client)
 
-	err = txSender.SendRawTransaction(ctx, txByte, true)
+	result, err := txSender.SendRawTransaction(ctx, txByte, true)
 	if err != nil {
 		panic(err)
 	}
 
+	println("builder:", result.Brand, "bundleID:", result.BundleID.String())
+
 	time.Sleep(time.Duration(cfg.Sender.BundleLifeNumber) * time.Duration(cfg.Sender.BlockInterval))
 	receipt, err := client.TransactionReceipt(ctx, txHash)
 	if err != nil {
@@ -108,12 +108,3 @@ func generateTx(client *ethclient.Client) (common.Hash, hexutil.Bytes) {
 
 	return signedTx.Hash(), rawTx
 }
-
-func LoadConfig(path string) Config {
-	var cfg Config
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
-		panic(err)
-	}
-
-	return cfg
-}
@@ -2,6 +2,7 @@ package builder
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -12,16 +13,21 @@ import (
 	"github.com/node-real/private-tx-sender/pkg/rpc"
 )
 
-const BlockrazorMethod = "eth_sendBundle"
+const (
+	BlockrazorMethod     = "eth_sendBundle"
+	BlockrazorCallMethod = "eth_callBundle"
+)
 
-func newBlockrazor(cfg Config) Builder {
-	return &blockrazor{
-		key: cfg.Key,
-		builder: &builder{
-			url:   cfg.URL,
-			brand: cfg.Brand,
-		},
+func newBlockrazor(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	return &blockrazor{
+		key:     cfg.Key,
+		builder: base,
+	}, nil
 }
 
 type blockrazor struct {
@@ -29,24 +35,57 @@ type blockrazor struct {
 	*builder
 }
 
-func (b *blockrazor) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) error {
+func (b *blockrazor) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) (BundleID, error) {
 	req, err := newBlockrazorRequest(args, bundleLifeNumber)
 	if err != nil {
 		log.Error("failed to create blockrazor jsonrpc request", "err", err)
-		return err
+		return "", err
 	}
 
 	opt := rpc.WithHeader(map[string]string{
 		"Authorization": b.key,
 	})
 
-	err = SendBundleCall(ctx, b.url, req, opt)
+	result, err := b.call(ctx, req, opt)
 	if err != nil {
 		log.Error("failed to send blockrazor bundle", "err", err)
-		return err
+		return "", err
+	}
+
+	return parseBundleID(b.brand, result), nil
+}
+
+func (b *blockrazor) CallBundle(ctx context.Context, args *types.SendBundleArgs) (json.RawMessage, error) {
+	body := blockrazorCallBody{
+		Txs:              args.Txs,
+		BlockNumber:      hexutil.Uint64(args.MaxBlockNumber),
+		StateBlockNumber: "latest",
+	}
+
+	bodybyte, err := jsoniter.Marshal(body)
+	if err != nil {
+		log.Error("failed to marshal blockrazor call body", "err", err)
+		return nil, err
+	}
+
+	req := &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  BlockrazorCallMethod,
+		Params:  []rpc.Param{bodybyte},
 	}
 
-	return nil
+	opt := rpc.WithHeader(map[string]string{
+		"Authorization": b.key,
+	})
+
+	result, err := b.call(ctx, req, opt)
+	if err != nil {
+		log.Error("failed to call blockrazor bundle", "err", err)
+		return nil, err
+	}
+
+	return result, nil
 }
 
 func (b *blockrazor) GetBrand() string {
@@ -61,6 +100,12 @@ type blockrazorBody struct {
 	RevertingTxHashes []common.Hash   `json:"revertingTxHashes,omitempty"`
 }
 
+type blockrazorCallBody struct {
+	Txs              []hexutil.Bytes `json:"txs"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	StateBlockNumber string          `json:"stateBlockNumber"`
+}
+
 func newBlockrazorRequest(args *types.SendBundleArgs, _ uint64) (*rpc.JsonrpcRequest, error) {
 	body := blockrazorBody{
 		Txs:               args.Txs,
@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -15,42 +16,71 @@ import (
 const (
 	BloxrouteBlockchainNetwork = "BSC-Mainnet"
 	BloxrouteMethod            = "blxr_submit_bundle"
+	// BloxrouteMaxBlocksCount is the longest block range bloxroute accepts for a bsc bundle
+	BloxrouteMaxBlocksCount = 20
 )
 
-func newBloxroute(cfg Config) Builder {
-	return &bloxroute{
-		key: cfg.Key,
-		builder: &builder{
-			url:   cfg.URL,
-			brand: cfg.Brand,
-		},
+// BloxrouteConfig holds the bloxroute specific options, a ws:// or wss:// url sends the bundles
+// over a persistent websocket connection instead of http.
+type BloxrouteConfig struct {
+	Network     string   // blockchain_network, BSC-Mainnet or BSC-Testnet
+	MevBuilders []string // builders bloxroute forwards the bundle to, e.g. all, empty lets bloxroute decide
+}
+
+func newBloxroute(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bloxroute{
+		key:     cfg.Key,
+		network: BloxrouteBlockchainNetwork,
+		builder: base,
+	}
+
+	if cfg.Bloxroute != nil {
+		if cfg.Bloxroute.Network != "" {
+			b.network = cfg.Bloxroute.Network
+		}
+		b.mevBuilders = cfg.Bloxroute.MevBuilders
+	}
+
+	// a websocket url is only used as the single endpoint of the builder
+	if len(base.urls) == 1 && (strings.HasPrefix(base.url, "ws://") || strings.HasPrefix(base.url, "wss://")) {
+		base.ws = newWSClient(base.url)
 	}
+
+	return b, nil
 }
 
 type bloxroute struct {
-	key string
+	key         string
+	network     string
+	mevBuilders []string
 	*builder
 }
 
 // SendBundle sends a bundle to bloxroute TODO customize bundler for paying to bloxroute builder
-func (b *bloxroute) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) error {
-	req, err := newBloxrouteRequest(args, bundleLifeNumber)
+func (b *bloxroute) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) (BundleID, error) {
+	req, err := b.newRequest(args, bundleLifeNumber)
 	if err != nil {
 		log.Error("failed to create bloxroute jsonrpc request", "err", err)
-		return err
+		return "", err
 	}
 
+	// the header authenticates the websocket connection when it is dialed
 	opt := rpc.WithHeader(map[string]string{
 		"Authorization": b.key,
 	})
 
-	err = SendBundleCall(ctx, b.url, req, opt)
+	result, err := b.call(ctx, req, opt)
 	if err != nil {
 		log.Error("failed to send bloxroute bundle", "err", err)
-		return err
+		return "", err
 	}
 
-	return nil
+	return parseBundleID(b.brand, result), nil
 }
 
 func (b *bloxroute) GetBrand() string {
@@ -58,22 +88,27 @@ func (b *bloxroute) GetBrand() string {
 }
 
 type bloxrouteBundleBody struct {
-	Transaction       []hexutil.Bytes `json:"transaction"`
-	BlockchainNetwork string          `json:"blockchain_network"`
-	BlockNumber       string          `json:"block_number"`
-	MaxTimestamp      uint64          `json:"max_timestamp"`
-	RevertingHashes   []common.Hash   `json:"reverting_hashes"`
+	Transaction       []hexutil.Bytes   `json:"transaction"`
+	BlockchainNetwork string            `json:"blockchain_network"`
+	BlockNumber       string            `json:"block_number"`
+	BlocksCount       uint64            `json:"blocks_count,omitempty"`
+	MaxTimestamp      uint64            `json:"max_timestamp"`
+	RevertingHashes   []common.Hash     `json:"reverting_hashes"`
+	MevBuilders       map[string]string `json:"mev_builders,omitempty"`
 }
 
-func newBloxrouteRequest(args *types.SendBundleArgs, bundleLifeNumber uint64) (*rpcRequest, error) {
+// newRequest targets the bundle window from the next block to MaxBlockNumber, capped at the
+// range bloxroute accepts.
+func (b *bloxroute) newRequest(args *types.SendBundleArgs, bundleLifeNumber uint64) (*rpcRequest, error) {
 	maxBlockNumber := args.MaxBlockNumber
 	nextBlockNumber := maxBlockNumber - bundleLifeNumber + 1
 	blockNumber := hexutil.EncodeBig(big.NewInt(int64(nextBlockNumber)))
 
 	body := bloxrouteBundleBody{
 		Transaction:       args.Txs,
-		BlockchainNetwork: BloxrouteBlockchainNetwork,
+		BlockchainNetwork: b.network,
 		BlockNumber:       blockNumber,
+		BlocksCount:       min(bundleLifeNumber, BloxrouteMaxBlocksCount),
 		RevertingHashes:   args.RevertingTxHashes,
 	}
 
@@ -81,6 +116,13 @@ func newBloxrouteRequest(args *types.SendBundleArgs, bundleLifeNumber uint64) (*
 		body.MaxTimestamp = *args.MaxTimestamp
 	}
 
+	if len(b.mevBuilders) > 0 {
+		body.MevBuilders = make(map[string]string, len(b.mevBuilders))
+		for _, name := range b.mevBuilders {
+			body.MevBuilders[name] = ""
+		}
+	}
+
 	return &rpcRequest{
 		ID:     1,
 		Method: BloxrouteMethod,
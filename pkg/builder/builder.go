@@ -3,12 +3,20 @@ package builder
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/gin-gonic/gin"
 	jsoniter "github.com/json-iterator/go"
@@ -24,69 +32,267 @@ const (
 	Txboost    Brand = "txboost"
 	Bloxroute  Brand = "bloxroute"
 	Blockrazor Brand = "blockrazor"
+	Club48     Brand = "48club"
 )
 
 type Config struct {
 	Brand Brand
 	URL   string
 	Key   string // api key for authentication
+	// URLs are more regional endpoints of the builder, they are called along with URL by Strategy
+	URLs       []string
+	Strategy   EndpointStrategy
+	HedgeDelay Duration // defaults to DefaultHedgeDelay
+	// Region picks URL from the catalog when no url is set, RegionAuto picks the fastest region
+	Region string
+	// KeepAlive is the interval of the requests keeping the connections to the endpoints open,
+	// DefaultKeepAlive when 0, see KeepWarm
+	KeepAlive Duration
+	// PinDNS resolves the hosts of the endpoints when warming them up instead of on every new connection
+	PinDNS bool
+	// SignerKey is the hex private key of the reputation identity signing the request bodies with
+	// X-Flashbots-Signature, for the builders authenticating by signature
+	SignerKey string
+	// SoulPointKey is the hex private key of a 48 SoulPoint member, it signs the bundles sent to 48club
+	SoulPointKey string
+	// Generic describes the bundle format of the generic brand, it is ignored by the other brands
+	Generic *GenericConfig
+	// Bloxroute holds the options of the bloxroute brand, it is ignored by the other brands
+	Bloxroute *BloxrouteConfig
 }
 
-func New(cfg Config) Builder {
-	switch cfg.Brand {
-	case Nodereal:
-		return newNodeReal(cfg)
-	case Puissant:
-		return newPuissant(cfg)
-	case Txboost:
-		return newTxboost(cfg)
-	case Bloxroute:
-		return newBloxroute(cfg)
-	case Blockrazor:
-		return newBlockrazor(cfg)
-	default:
-		log.Crit("invalid builder brand", "brand", cfg.Brand)
+type Builder interface {
+	SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) (BundleID, error)
+	GetBrand() string
+}
+
+// configHasher is implemented by the builders created on the base builder of the package.
+type configHasher interface {
+	configHash() string
+}
+
+// ID identifies a builder by its brand and a hash of its config: the builders of a brand with
+// different endpoints or keys get different ids, a builder created again from the same config gets
+// the same one. The builders of other packages are identified by their brand.
+func ID(b Builder) string {
+	if h, ok := b.(configHasher); ok {
+		return b.GetBrand() + "-" + h.configHash()
 	}
 
-	return nil
+	return b.GetBrand()
 }
 
-type Builder interface {
-	SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) error
-	GetBrand() string
+// BundleID identifies a bundle accepted by a builder, it is the bundle hash for the brands documenting
+// one and the raw id returned by the builder otherwise.
+type BundleID string
+
+func (id BundleID) String() string {
+	return string(id)
+}
+
+var ErrInvalidBundleID = errors.New("invalid bundle id")
+
+// hashBundleIDs are the brands documenting a bundle hash as the result of their send method.
+var hashBundleIDs = map[Brand]bool{
+	Nodereal:    true,
+	Puissant:    true,
+	Txboost:     true,
+	Bloxroute:   true,
+	Blockrazor:  true,
+	Club48:      true,
+	Flashbots:   true,
+	Beaverbuild: true,
+	Titan:       true,
+	Rsync:       true,
+}
+
+// parseBundleID extracts the id of an accepted bundle, either a bare value or an object carrying it
+// as bundleHash. The builder accepted the bundle, so an id which is not the hash documented by the
+// brand is kept as it is with a warning rather than failing the send.
+func parseBundleID(brand Brand, result json.RawMessage) BundleID {
+	var id string
+	if err := jsoniter.Unmarshal(result, &id); err != nil {
+		obj := struct {
+			BundleHash string `json:"bundleHash"`
+		}{}
+		if err := jsoniter.Unmarshal(result, &obj); err != nil || obj.BundleHash == "" {
+			log.Warn("unexpected bundle id, keep the raw result", "brand", brand, "result", string(result))
+			return BundleID(result)
+		}
+
+		id = obj.BundleHash
+	}
+
+	if !hashBundleIDs[brand] {
+		return BundleID(id)
+	}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(id, "0x"))
+	if err != nil || len(raw) != common.HashLength || common.BytesToHash(raw) == (common.Hash{}) {
+		log.Warn("bundle id is not a bundle hash, keep it as it is", "brand", brand, "id", id)
+		return BundleID(id)
+	}
+
+	return BundleID(common.BytesToHash(raw).Hex())
 }
 
 type builder struct {
-	brand Brand
-	url   string
+	brand      Brand
+	url        string   // the first endpoint
+	urls       []string // all endpoints
+	strategy   EndpointStrategy
+	hedgeDelay time.Duration
+	signer     rpc.RequestSigner // nil unless Config.SignerKey is set
+	ws         *wsClient         // set by the brands sending over a websocket url, nil for http
+	cfg        Config            // kept to warm up the endpoints
+	hash       string            // of cfg, see ID
+}
+
+func newBuilder(cfg Config) (*builder, error) {
+	urls := cfg.Endpoints()
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no url configured for builder %s", cfg.Brand)
+	}
+
+	if !cfg.Strategy.Valid() {
+		return nil, fmt.Errorf("unknown endpoint strategy %q", cfg.Strategy)
+	}
+
+	b := &builder{
+		brand:      cfg.Brand,
+		url:        urls[0],
+		urls:       urls,
+		strategy:   cfg.Strategy,
+		hedgeDelay: time.Duration(cfg.HedgeDelay),
+		cfg:        cfg,
+	}
+
+	if b.hedgeDelay <= 0 {
+		b.hedgeDelay = DefaultHedgeDelay
+	}
+
+	// the endpoint picked for an auto region is left out, so the id does not depend on the fastest region
+	hashed := cfg
+	if cfg.Region == RegionAuto {
+		hashed.URL, hashed.URLs = "", nil
+	}
+
+	cfgByte, err := jsoniter.Marshal(&hashed)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(cfgByte)
+	b.hash = hex.EncodeToString(sum[:4])
+
+	if cfg.SignerKey != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.SignerKey, "0x"))
+		if err != nil {
+			log.Error("invalid builder signer key", "brand", cfg.Brand, "err", err)
+			return nil, err
+		}
+
+		b.signer = rpc.FlashbotsSigner(key)
+	}
+
+	return b, nil
+}
+
+func (b *builder) configHash() string {
+	return b.hash
+}
+
+// Close closes the websocket connection of the builder, if it has one.
+func (b *builder) Close() error {
+	if b.ws == nil {
+		return nil
+	}
+
+	return b.ws.Close()
+}
+
+// call sends the request to the endpoints of the builder by its strategy, signed when the builder
+// has a signer.
+func (b *builder) call(ctx context.Context, req interface{}, options ...rpc.CallOption) (json.RawMessage, error) {
+	if b.signer != nil {
+		options = append(options, rpc.WithSigner(b.signer))
+	}
+
+	if b.ws != nil {
+		return b.ws.Call(ctx, req, options...)
+	}
+
+	if len(b.urls) == 1 {
+		return SendBundleCall(ctx, b.url, req, options...)
+	}
+
+	switch b.strategy {
+	case StrategyRace:
+		return b.hedge(ctx, req, 0, options)
+	case StrategyHedge:
+		return b.hedge(ctx, req, b.hedgeDelay, options)
+	default:
+		return b.failover(ctx, req, options)
+	}
 }
 
-func SendBundleCall(ctx context.Context, url string, req interface{}, options ...rpc.CallOption) error {
+// SendBundleCall posts a jsonrpc request to the builder and returns the raw result of the call.
+func SendBundleCall(ctx context.Context, url string, req interface{}, options ...rpc.CallOption) (json.RawMessage, error) {
 	opt := &rpc.CallOptions{Header: map[string]string{"Content-Type": gin.MIMEJSON}}
 	opt.ApplyOptions(options...)
 
 	reqByte, err := jsoniter.Marshal(req)
 	if err != nil {
 		log.Error("failed to marshal jsonrpc request body", "url", url, "err", err)
-		return err
+		return nil, err
 	}
 
 	httpReq, httpErr := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqByte))
 	if httpErr != nil {
 		log.Error("failed to create jsonrpc http request", "url", url, "err", httpErr)
-		return err
+		return nil, httpErr
 	}
 
 	for k, v := range opt.Header {
 		httpReq.Header.Set(k, v)
 	}
 
+	if opt.Signer != nil {
+		header, err := opt.Signer(reqByte)
+		if err != nil {
+			log.Error("failed to sign jsonrpc request", "url", url, "err", err)
+			return nil, err
+		}
+
+		for k, v := range header {
+			httpReq.Header.Set(k, v)
+		}
+	}
+
+	// the time to first byte tells the gain of a warm connection over a new one
+	var (
+		reused bool
+		ttfb   time.Duration
+	)
+	start := time.Now()
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn:              func(info httptrace.GotConnInfo) { reused = info.Reused },
+		GotFirstResponseByte: func() { ttfb = time.Since(start) },
+	}))
+
 	httpResp, err := rpc.HTTPClient.Do(httpReq)
+	EndpointLatency.WithLabelValues(url).Observe(time.Since(start).Seconds())
+	if ttfb > 0 {
+		conn := "new"
+		if reused {
+			conn = "reused"
+		}
+		EndpointTTFB.WithLabelValues(url, conn).Observe(ttfb.Seconds())
+	}
 	if err != nil {
 		ErrorCounter.WithLabelValues(url).Inc()
 
 		log.Error("failed to send jsonrpc http request", "url", url, "err", err)
-		return err
+		return nil, err
 	}
 	defer httpResp.Body.Close()
 
@@ -94,20 +300,20 @@ func SendBundleCall(ctx context.Context, url string, req interface{}, options ..
 		ErrorCounter.WithLabelValues(url).Inc()
 
 		log.Error("failed to send jsonrpc http request", "url", url, "code", httpResp.StatusCode)
-		return fmt.Errorf("failed to send jsonrpc http request, code: %d", httpResp.StatusCode)
+		return nil, fmt.Errorf("failed to send jsonrpc http request, code: %d", httpResp.StatusCode)
 	}
 
 	body, err := ioutil.ReadAll(httpResp.Body)
 	if err != nil {
 		log.Error("failed to read response of jsonrpc call", "url", url, "err", err)
-		return err
+		return nil, err
 	}
 
 	resp := rpc.JsonrpcResponse{}
 	err = jsoniter.Unmarshal(body, &resp)
 	if err != nil {
 		log.Error("failed to unmarshal response of jsonrpc call", "url", url, "err", err)
-		return err
+		return nil, err
 	}
 
 	if resp.Error != nil {
@@ -117,18 +323,18 @@ func SendBundleCall(ctx context.Context, url string, req interface{}, options ..
 		err = jsoniter.Unmarshal(*resp.Error, &jrError)
 		if err != nil {
 			log.Error("failed to unmarshal resp.Error", "url", url, "err", err)
-			return err
+			return nil, err
 		}
 
 		if jrError.Code == rpc.InternalErrorCode {
 			log.Error(" response internal error", "url", url)
-			return errors.New(" response internal error")
+			return nil, errors.New(" response internal error")
 		}
 
 		log.Error(" response error", "code", jrError.Code, "message", jrError.Message)
-		return errors.New(jrError.Message)
+		return nil, errors.New(jrError.Message)
 	}
 
-	log.Info("send bundle success", "url", url, "bundle_hash", resp.Result)
-	return nil
+	log.Info("send bundle success", "url", url, "result", string(resp.Result))
+	return resp.Result, nil
 }
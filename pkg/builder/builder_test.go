@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseBundleID(t *testing.T) {
+	const hash = "0x6f2b4f1a6f0e8c4a1f3a5b8e0c2d4f6a8b0c2e4f6a8b0d2f4e6a8c0e2f4a6b8d"
+
+	tests := []struct {
+		name   string
+		brand  Brand
+		result string
+		want   BundleID
+	}{
+		{"bare hash", Nodereal, `"` + hash + `"`, hash},
+		{"object hash", Flashbots, `{"bundleHash":"` + hash + `"}`, hash},
+		{"hash without prefix", Blockrazor, `"` + hash[2:] + `"`, hash},
+		{"uuid kept for hash brand", Txboost, `"0d3b1c5e-7f4b-4f2e-9a51-2b3c4d5e6f70"`, "0d3b1c5e-7f4b-4f2e-9a51-2b3c4d5e6f70"},
+		{"zero hash kept for hash brand", Nodereal, `"0x0000000000000000000000000000000000000000000000000000000000000000"`,
+			"0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{"raw id of generic brand", Generic, `"bundle-42"`, "bundle-42"},
+		{"unexpected result kept raw", Bloxroute, `{"status":"ok"}`, `{"status":"ok"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBundleID(tt.brand, json.RawMessage(tt.result)); got != tt.want {
+				t.Errorf("parseBundleID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestID(t *testing.T) {
+	usEast := Config{Brand: Nodereal, URL: "https://bsc-mainnet-builder-us.nodereal.io", Key: "key"}
+	frankfurt := Config{Brand: Nodereal, URL: "https://bsc-mainnet-builder-eu.nodereal.io", Key: "key"}
+
+	tests := []struct {
+		name     string
+		a, b     Config
+		wantSame bool
+	}{
+		{"same config", usEast, usEast, true},
+		{"same brand, other url", usEast, frankfurt, false},
+		{"same url, other key", usEast, Config{Brand: Nodereal, URL: usEast.URL, Key: "other"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := New(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if same := ID(a) == ID(b); same != tt.wantSame {
+				t.Fatalf("ID() = %s and %s, want same %v", ID(a), ID(b), tt.wantSame)
+			}
+			if !strings.HasPrefix(ID(a), string(tt.a.Brand)+"-") {
+				t.Fatalf("ID() = %s, want the brand as prefix", ID(a))
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BundleSimulator is implemented by the builders which simulate bundles with eth_callBundle.
+type BundleSimulator interface {
+	CallBundle(ctx context.Context, args *types.SendBundleArgs) (json.RawMessage, error)
+}
+
+// BundleCanceller is implemented by the builders which accept cancelling a bundle sent before.
+type BundleCanceller interface {
+	CancelBundle(ctx context.Context, id BundleID) error
+}
+
+// BundleResender is implemented by the builders accepting a bundle for a single block only,
+// the sender resends the bundle for every block of its window until it is included.
+type BundleResender interface {
+	ResendBundle(ctx context.Context, id BundleID, args *types.SendBundleArgs, blockNumber uint64) error
+}
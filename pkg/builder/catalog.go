@@ -0,0 +1,163 @@
+package builder
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// RegionAuto keeps every region of the catalog, the one answering the fastest is picked when the
+// builder is first created and reused afterwards.
+const RegionAuto = "auto"
+
+// regionProbeTimeout bounds the latency measurement of the regions of a builder.
+const regionProbeTimeout = 3 * time.Second
+
+// Credential tells what a builder of the catalog expects from the config.
+type Credential string
+
+const (
+	CredentialNone   Credential = "none"
+	CredentialKey    Credential = "key"    // Config.Key, sent in the Authorization header
+	CredentialSigner Credential = "signer" // Config.SignerKey, signing the request bodies
+)
+
+// CatalogEntry is a known endpoint of a builder.
+type CatalogEntry struct {
+	ChainID    uint64
+	Brand      Brand
+	Region     string
+	URL        string
+	Credential Credential
+}
+
+const (
+	chainIDEthereum uint64 = 1
+	chainIDBSC      uint64 = 56
+)
+
+// catalog only lists the endpoints published by the builders, more regions are added to URLs in the config.
+var catalog = []CatalogEntry{
+	{chainIDBSC, Nodereal, "us", "https://bsc-mainnet-builder-us.nodereal.io", CredentialNone},
+	{chainIDBSC, Club48, "global", "https://puissant-builder.48.club", CredentialNone},
+	{chainIDBSC, Blockrazor, "frankfurt", "https://blockrazor-builder-frankfurt.48.club", CredentialKey},
+	{chainIDBSC, Txboost, "us", "https://fastbundle-us.blocksmith.org", CredentialKey},
+
+	{chainIDEthereum, Flashbots, "global", "https://relay.flashbots.net", CredentialSigner},
+	{chainIDEthereum, Beaverbuild, "global", "https://rpc.beaverbuild.org", CredentialNone},
+	{chainIDEthereum, Titan, "global", "https://rpc.titanbuilder.xyz", CredentialNone},
+	{chainIDEthereum, Titan, "eu", "https://eu.rpc.titanbuilder.xyz", CredentialNone},
+	{chainIDEthereum, Titan, "us", "https://us.rpc.titanbuilder.xyz", CredentialNone},
+	{chainIDEthereum, Titan, "ap", "https://ap.rpc.titanbuilder.xyz", CredentialNone},
+	{chainIDEthereum, Rsync, "global", "https://rsync-builder.xyz", CredentialNone},
+}
+
+// Catalog returns the known endpoints of the brand on the chain.
+func Catalog(chainID uint64, brand Brand) []CatalogEntry {
+	var entries []CatalogEntry
+	for _, entry := range catalog {
+		if entry.ChainID == chainID && entry.Brand == brand {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// regionPicks caches the url picked for the endpoints of a builder with RegionAuto, keyed by
+// regionKey. An empty url means no region answered and all of them are kept.
+var regionPicks sync.Map
+
+func regionKey(cfg Config) string {
+	return string(cfg.Brand) + " " + strings.Join(cfg.Endpoints(), " ")
+}
+
+// pickRegion keeps the fastest endpoint of a builder with RegionAuto. The endpoints are probed once
+// per process, the builders created again on a reload reuse the pick.
+func pickRegion(cfg Config) Config {
+	if len(cfg.Endpoints()) < 2 {
+		return cfg
+	}
+
+	key := regionKey(cfg)
+	picked, ok := regionPicks.Load(key)
+	if !ok {
+		picked, _ = regionPicks.LoadOrStore(key, probeRegions(cfg))
+	}
+
+	if url := picked.(string); url != "" {
+		cfg.URL, cfg.URLs = url, nil
+	}
+
+	return cfg
+}
+
+// probeRegions pings every endpoint of the builder and returns the fastest one, it is empty when
+// none answers.
+func probeRegions(cfg Config) string {
+	endpoints := cfg.Endpoints()
+
+	ctx, cancel := context.WithTimeout(context.Background(), regionProbeTimeout)
+	defer cancel()
+
+	type probe struct {
+		url     string
+		latency time.Duration
+		err     error
+	}
+
+	probes := make(chan probe, len(endpoints))
+	for _, url := range endpoints {
+		go func(url string) {
+			endpoint := cfg
+			endpoint.URL, endpoint.URLs = url, nil
+
+			latency, err := Ping(ctx, endpoint)
+			probes <- probe{url: url, latency: latency, err: err}
+		}(url)
+	}
+
+	var reachable []probe
+	for range endpoints {
+		p := <-probes
+		if p.err != nil {
+			log.Warn("builder region unreachable", "brand", cfg.Brand, "url", p.url, "err", p.err)
+			continue
+		}
+
+		reachable = append(reachable, p)
+	}
+
+	if len(reachable) == 0 {
+		log.Warn("no builder region answered, keep all of them", "brand", cfg.Brand)
+		return ""
+	}
+
+	sort.Slice(reachable, func(i, j int) bool { return reachable[i].latency < reachable[j].latency })
+	log.Info("picked builder region", "brand", cfg.Brand, "url", reachable[0].url, "latency", reachable[0].latency)
+
+	return reachable[0].url
+}
+
+// pickRegions probes the builders with RegionAuto at once, so that creating them waits for the
+// slowest probe rather than the sum of them.
+func pickRegions(configs []Config) {
+	var wg sync.WaitGroup
+	for _, cfg := range configs {
+		if cfg.Region != RegionAuto {
+			continue
+		}
+
+		wg.Add(1)
+		go func(cfg Config) {
+			defer wg.Done()
+			pickRegion(cfg)
+		}(cfg)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPickRegion(t *testing.T) {
+	newServer := func(delay time.Duration, code int, pings *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(pings, 1)
+			time.Sleep(delay)
+			w.WriteHeader(code)
+		}))
+	}
+
+	var pings int32
+	fast := newServer(0, http.StatusOK, &pings)
+	defer fast.Close()
+	slow := newServer(100*time.Millisecond, http.StatusOK, &pings)
+	defer slow.Close()
+	failing := newServer(0, http.StatusBadGateway, &pings)
+	defer failing.Close()
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantURL string
+		wantLen int
+	}{
+		{"fastest region", Config{Brand: Titan, URL: slow.URL, URLs: []string{fast.URL}, Region: RegionAuto}, fast.URL, 1},
+		{"failing region skipped", Config{Brand: Titan, URL: failing.URL, URLs: []string{slow.URL}, Region: RegionAuto}, slow.URL, 1},
+		{"all kept when none answers", Config{Brand: Rsync, URL: failing.URL, URLs: []string{failing.URL + "/b"}, Region: RegionAuto}, failing.URL, 2},
+		{"single endpoint", Config{Brand: Rsync, URL: slow.URL, Region: RegionAuto}, slow.URL, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickRegion(tt.cfg)
+			if got.URL != tt.wantURL || len(got.Endpoints()) != tt.wantLen {
+				t.Fatalf("pickRegion() = %s %v, want %s with %d endpoints", got.URL, got.URLs, tt.wantURL, tt.wantLen)
+			}
+
+			// the pick is cached, a builder created again does not probe
+			before := atomic.LoadInt32(&pings)
+			if again := pickRegion(tt.cfg); again.URL != got.URL || len(again.Endpoints()) != tt.wantLen {
+				t.Fatalf("cached pickRegion() = %s %v, want %s", again.URL, again.URLs, got.URL)
+			}
+
+			if after := atomic.LoadInt32(&pings); after != before {
+				t.Fatalf("cached pickRegion() pinged %d endpoints", after-before)
+			}
+		})
+	}
+}
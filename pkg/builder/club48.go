@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+const Club48Method = "eth_sendBundle"
+
+func newClub48(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &club48{
+		builder: base,
+	}
+
+	if cfg.SoulPointKey != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.SoulPointKey, "0x"))
+		if err != nil {
+			log.Error("invalid 48 SoulPoint key", "err", err)
+			return nil, err
+		}
+
+		b.soulPointKey = key
+	}
+
+	return b, nil
+}
+
+// club48 sends to the 48 Club builder, bundles are signed when a 48 SoulPoint member key is configured
+// so they get the priority of the member.
+type club48 struct {
+	*builder
+	soulPointKey *ecdsa.PrivateKey
+}
+
+func (b *club48) SendBundle(ctx context.Context, args *types.SendBundleArgs, _ uint64) (BundleID, error) {
+	req, err := b.newRequest(args)
+	if err != nil {
+		log.Error("failed to create 48club jsonrpc request", "err", err)
+		return "", err
+	}
+
+	result, err := b.call(ctx, req)
+	if err != nil {
+		log.Error("failed to send 48club bundle", "err", err)
+		return "", err
+	}
+
+	return parseBundleID(b.brand, result), nil
+}
+
+func (b *club48) GetBrand() string {
+	return string(b.brand)
+}
+
+type club48Body struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	MaxBlockNumber    uint64          `json:"maxBlockNumber,omitempty"`
+	MaxTimestamp      uint64          `json:"maxTimestamp,omitempty"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes,omitempty"`
+	SoulPointSign     hexutil.Bytes   `json:"48spSign,omitempty"`
+}
+
+func (b *club48) newRequest(args *types.SendBundleArgs) (*rpc.JsonrpcRequest, error) {
+	body := &club48Body{
+		Txs:               args.Txs,
+		MaxBlockNumber:    args.MaxBlockNumber,
+		RevertingTxHashes: args.RevertingTxHashes,
+	}
+
+	if args.MaxTimestamp != nil {
+		body.MaxTimestamp = *args.MaxTimestamp
+	}
+
+	if b.soulPointKey != nil {
+		sign, err := soulPointSign(args.Txs, b.soulPointKey)
+		if err != nil {
+			log.Error("failed to sign 48club bundle", "err", err)
+			return nil, err
+		}
+
+		body.SoulPointSign = sign
+	}
+
+	bodybytes, err := jsoniter.Marshal(body)
+	if err != nil {
+		log.Error("failed to marshal 48club body", "err", err)
+		return nil, err
+	}
+
+	return &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  Club48Method,
+		Params:  []rpc.Param{bodybytes},
+	}, nil
+}
+
+// soulPointSign signs the keccak256 of the concatenated hashes of the bundle txs.
+func soulPointSign(txs []hexutil.Bytes, key *ecdsa.PrivateKey) (hexutil.Bytes, error) {
+	hashes := make([]byte, 0, len(txs)*common.HashLength)
+	for _, input := range txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, err
+		}
+
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+
+	return crypto.Sign(crypto.Keccak256(hashes), key)
+}
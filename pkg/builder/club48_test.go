@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestClub48SoulPointSign(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := make([]hexutil.Bytes, 2)
+	hashes := make([]byte, 0, 64)
+	for i := range txs {
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000})
+		if txs[i], err = tx.MarshalBinary(); err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		wantSign bool
+	}{
+		{"member key", hexutil.Encode(crypto.FromECDSA(key)), true},
+		{"no key", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newClub48(Config{Brand: Club48, URL: "https://puissant-builder.48.club", SoulPointKey: tt.key})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := b.(*club48).newRequest(&types.SendBundleArgs{Txs: txs, MaxBlockNumber: 100})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body := club48Body{}
+			if err := json.Unmarshal(req.Params[0], &body); err != nil {
+				t.Fatal(err)
+			}
+
+			if (len(body.SoulPointSign) > 0) != tt.wantSign {
+				t.Fatalf("48spSign = %s, want signed %v", body.SoulPointSign, tt.wantSign)
+			}
+
+			if !tt.wantSign {
+				return
+			}
+
+			pub, err := crypto.SigToPub(crypto.Keccak256(hashes), body.SoulPointSign)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if crypto.PubkeyToAddress(*pub) != crypto.PubkeyToAddress(key.PublicKey) {
+				t.Fatal("48spSign is not the signature of the tx hashes by the member key")
+			}
+		})
+	}
+}
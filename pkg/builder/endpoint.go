@@ -0,0 +1,166 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/hashicorp/go-multierror"
+	"github.com/tredeske/u/ustrings"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+// EndpointStrategy decides how a call is spread over the regional endpoints of a builder.
+type EndpointStrategy string
+
+const (
+	// StrategyFailover tries the endpoints in order until one succeeds, the default
+	StrategyFailover EndpointStrategy = "failover"
+	// StrategyRace sends to all endpoints at once and takes the first success
+	StrategyRace EndpointStrategy = "race"
+	// StrategyHedge sends to the next endpoint when the previous ones failed or did not answer within HedgeDelay
+	StrategyHedge EndpointStrategy = "hedge"
+)
+
+const DefaultHedgeDelay = 50 * time.Millisecond
+
+// Duration reads a time.Duration from text like "50ms" in every config format.
+type Duration time.Duration
+
+func (d *Duration) MarshalText() ([]byte, error) {
+	return ustrings.UnsafeStringToBytes(time.Duration(*d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	dd, err := time.ParseDuration(ustrings.UnsafeBytesToString(text))
+	*d = Duration(dd)
+	return err
+}
+
+func (s EndpointStrategy) Valid() bool {
+	switch s {
+	case "", StrategyFailover, StrategyRace, StrategyHedge:
+		return true
+	}
+
+	return false
+}
+
+// Endpoints returns URL followed by URLs without duplicates.
+func (c *Config) Endpoints() []string {
+	endpoints := make([]string, 0, len(c.URLs)+1)
+	seen := make(map[string]struct{}, len(c.URLs)+1)
+	for _, url := range append([]string{c.URL}, c.URLs...) {
+		if _, ok := seen[url]; ok || url == "" {
+			continue
+		}
+
+		seen[url] = struct{}{}
+		endpoints = append(endpoints, url)
+	}
+
+	return endpoints
+}
+
+type endpointResponse struct {
+	url    string
+	result json.RawMessage
+	err    error
+}
+
+func (b *builder) failover(ctx context.Context, req interface{}, options []rpc.CallOption) (json.RawMessage, error) {
+	var errs error
+	for _, url := range b.urls {
+		result, err := SendBundleCall(ctx, url, req, options...)
+		if err == nil {
+			EndpointWinCounter.WithLabelValues(string(b.brand), url).Inc()
+			return result, nil
+		}
+
+		log.Warn("builder endpoint failed, fail over", "builder", b.brand, "url", url, "err", err)
+		errs = multierror.Append(errs, fmt.Errorf("%s: %w", url, err))
+	}
+
+	return nil, errs
+}
+
+// hedge starts with the first endpoint and adds the next one every delay, or as soon as one fails,
+// a zero delay sends to all endpoints at once. The first success is returned and the answers of
+// the other endpoints are drained as duplicates.
+func (b *builder) hedge(ctx context.Context, req interface{}, delay time.Duration, options []rpc.CallOption) (json.RawMessage, error) {
+	responses := make(chan endpointResponse, len(b.urls))
+	launched := 0
+	launch := func() {
+		url := b.urls[launched]
+		launched++
+
+		go func() {
+			result, err := SendBundleCall(ctx, url, req, options...)
+			responses <- endpointResponse{url: url, result: result, err: err}
+		}()
+	}
+
+	launch()
+	for delay == 0 && launched < len(b.urls) {
+		launch()
+	}
+
+	var timeout <-chan time.Time
+	if launched < len(b.urls) {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var errs error
+	for received := 0; received < len(b.urls); {
+		select {
+		case <-timeout:
+			timeout = nil
+			if launched < len(b.urls) {
+				log.Debug("builder endpoint slow, hedge", "builder", b.brand, "url", b.urls[launched-1])
+				launch()
+			}
+
+			if launched < len(b.urls) {
+				timeout = time.After(delay)
+			}
+		case resp := <-responses:
+			received++
+			if resp.err == nil {
+				EndpointWinCounter.WithLabelValues(string(b.brand), resp.url).Inc()
+				go b.drainDuplicates(responses, launched-received, resp.result)
+				return resp.result, nil
+			}
+
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", resp.url, resp.err))
+			if launched < len(b.urls) {
+				launch()
+			} else if received == launched {
+				return nil, errs
+			}
+		}
+	}
+
+	return nil, errs
+}
+
+// drainDuplicates waits for the endpoints still in flight, their successful answers are duplicates
+// of the returned one and are only counted, a different answer is logged.
+func (b *builder) drainDuplicates(responses <-chan endpointResponse, pending int, result json.RawMessage) {
+	for i := 0; i < pending; i++ {
+		resp := <-responses
+		if resp.err != nil {
+			continue
+		}
+
+		EndpointDuplicateCounter.WithLabelValues(string(b.brand), resp.url).Inc()
+		if string(resp.result) != string(result) {
+			log.Warn("builder endpoints answered differently", "builder", b.brand, "url", resp.url,
+				"result", string(resp.result), "returned", string(result))
+		}
+	}
+}
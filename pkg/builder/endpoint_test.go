@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEndpointStrategies(t *testing.T) {
+	newServer := func(delay time.Duration, code int, result string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"` + result + `"}`))
+		}))
+	}
+
+	fast := newServer(0, http.StatusOK, "fast")
+	defer fast.Close()
+	slow := newServer(300*time.Millisecond, http.StatusOK, "slow")
+	defer slow.Close()
+	failing := newServer(0, http.StatusInternalServerError, "")
+	defer failing.Close()
+
+	tests := []struct {
+		name       string
+		strategy   EndpointStrategy
+		hedgeDelay time.Duration
+		urls       []string
+		want       string
+		within     time.Duration
+		wantErr    bool
+	}{
+		{"failover to the next endpoint", StrategyFailover, 0, []string{failing.URL, fast.URL}, "fast", time.Second, false},
+		{"failover keeps the first success", StrategyFailover, 0, []string{slow.URL, fast.URL}, "slow", time.Second, false},
+		{"race takes the first answer", StrategyRace, 0, []string{slow.URL, fast.URL}, "fast", 200 * time.Millisecond, false},
+		{"hedge after the delay", StrategyHedge, 20 * time.Millisecond, []string{slow.URL, fast.URL}, "fast", 200 * time.Millisecond, false},
+		{"hedge on failure", StrategyHedge, time.Minute, []string{failing.URL, fast.URL}, "fast", time.Second, false},
+		{"failover all failed", StrategyFailover, 0, []string{failing.URL, failing.URL + "/other"}, "", time.Second, true},
+		{"race all failed", StrategyRace, 0, []string{failing.URL, failing.URL + "/other"}, "", time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newBuilder(Config{Brand: Nodereal, URLs: tt.urls, Strategy: tt.strategy, HedgeDelay: Duration(tt.hedgeDelay)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			result, err := b.call(context.Background(), map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "eth_sendBundle"})
+			if elapsed := time.Since(start); elapsed > tt.within {
+				t.Errorf("call() took %v, want within %v", elapsed, tt.within)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("call() = %s, want an error", result)
+				}
+				return
+			}
+
+			if err != nil || string(result) != `"`+tt.want+`"` {
+				t.Fatalf("call() = %s, %v, want %q", result, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+// Ethereum mainnet builders, the flashbots relay requires Config.SignerKey.
+const (
+	Flashbots   Brand = "flashbots"
+	Beaverbuild Brand = "beaverbuild"
+	Titan       Brand = "titan"
+	Rsync       Brand = "rsync"
+)
+
+const (
+	EthSendBundleMethod   = "eth_sendBundle"
+	EthCancelBundleMethod = "eth_cancelBundle"
+)
+
+// replacementTTL bounds how long the replacement uuid of a bundle is kept for resends and cancels.
+const replacementTTL = time.Hour
+
+var ErrUnknownBundle = fmt.Errorf("%w: bundle was not sent through this builder", ErrInvalidBundleID)
+
+func newEthBuilder(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethBuilder{
+		key:          cfg.Key,
+		builder:      base,
+		replacements: make(map[BundleID]replacement),
+	}, nil
+}
+
+// ethBuilder speaks the flashbots eth_sendBundle shared by the ethereum builders. Every bundle
+// gets a replacement uuid, which is kept across resends and used to cancel the bundle.
+type ethBuilder struct {
+	key string
+	*builder
+
+	mu           sync.Mutex
+	replacements map[BundleID]replacement
+}
+
+type replacement struct {
+	uuid    string
+	created time.Time
+}
+
+func (b *ethBuilder) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) (BundleID, error) {
+	replacementUUID := uuid.NewString()
+
+	bundleID, err := b.send(ctx, args, args.MaxBlockNumber-bundleLifeNumber+1, replacementUUID)
+	if err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for id, r := range b.replacements {
+		if now.Sub(r.created) > replacementTTL {
+			delete(b.replacements, id)
+		}
+	}
+	b.replacements[bundleID] = replacement{uuid: replacementUUID, created: now}
+
+	return bundleID, nil
+}
+
+func (b *ethBuilder) ResendBundle(ctx context.Context, id BundleID, args *types.SendBundleArgs, blockNumber uint64) error {
+	replacementUUID, err := b.replacementUUID(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.send(ctx, args, blockNumber, replacementUUID)
+	return err
+}
+
+func (b *ethBuilder) CancelBundle(ctx context.Context, id BundleID) error {
+	replacementUUID, err := b.replacementUUID(id)
+	if err != nil {
+		return err
+	}
+
+	bodybyte, err := jsoniter.Marshal(&ethCancelBody{ReplacementUUID: replacementUUID})
+	if err != nil {
+		log.Error("failed to marshal eth cancel body", "builder", b.brand, "err", err)
+		return err
+	}
+
+	req := &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  EthCancelBundleMethod,
+		Params:  []rpc.Param{bodybyte},
+	}
+
+	if _, err := b.call(ctx, req, b.authOptions()...); err != nil {
+		log.Error("failed to cancel eth bundle", "builder", b.brand, "err", err)
+		return err
+	}
+
+	b.mu.Lock()
+	delete(b.replacements, id)
+	b.mu.Unlock()
+
+	return nil
+}
+
+func (b *ethBuilder) GetBrand() string {
+	return string(b.brand)
+}
+
+func (b *ethBuilder) replacementUUID(id BundleID) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r, ok := b.replacements[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownBundle, id)
+	}
+
+	return r.uuid, nil
+}
+
+func (b *ethBuilder) authOptions() []rpc.CallOption {
+	if b.key == "" {
+		return nil
+	}
+
+	return []rpc.CallOption{rpc.WithHeader(map[string]string{"Authorization": b.key})}
+}
+
+type ethBundleBody struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	MinTimestamp      uint64          `json:"minTimestamp,omitempty"`
+	MaxTimestamp      uint64          `json:"maxTimestamp,omitempty"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes,omitempty"`
+	ReplacementUUID   string          `json:"replacementUuid,omitempty"`
+}
+
+type ethCancelBody struct {
+	ReplacementUUID string `json:"replacementUuid"`
+}
+
+func (b *ethBuilder) send(ctx context.Context, args *types.SendBundleArgs, blockNumber uint64, replacementUUID string) (BundleID, error) {
+	body := &ethBundleBody{
+		Txs:               args.Txs,
+		BlockNumber:       hexutil.Uint64(blockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+		ReplacementUUID:   replacementUUID,
+	}
+
+	if args.MinTimestamp != nil {
+		body.MinTimestamp = *args.MinTimestamp
+	}
+
+	if args.MaxTimestamp != nil {
+		body.MaxTimestamp = *args.MaxTimestamp
+	}
+
+	bodybyte, err := jsoniter.Marshal(body)
+	if err != nil {
+		log.Error("failed to marshal eth bundle body", "builder", b.brand, "err", err)
+		return "", err
+	}
+
+	req := &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  EthSendBundleMethod,
+		Params:  []rpc.Param{bodybyte},
+	}
+
+	result, err := b.call(ctx, req, b.authOptions()...)
+	if err != nil {
+		log.Error("failed to send eth bundle", "builder", b.brand, "block", blockNumber, "err", err)
+		return "", err
+	}
+
+	return parseBundleID(b.brand, result), nil
+}
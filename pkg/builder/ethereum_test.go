@@ -0,0 +1,136 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+type ethRequest struct {
+	Method    string
+	Body      map[string]interface{}
+	Signature string
+	RawBody   []byte
+}
+
+func TestEthBuilderReplacement(t *testing.T) {
+	const bundleHash = "0x6f2b4f1a6f0e8c4a1f3a5b8e0c2d4f6a8b0c2e4f6a8b0d2f4e6a8c0e2f4a6b8d"
+
+	var (
+		mu   sync.Mutex
+		reqs []ethRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		req := struct {
+			Method string                   `json:"method"`
+			Params []map[string]interface{} `json:"params"`
+		}{}
+		_ = json.Unmarshal(raw, &req)
+
+		mu.Lock()
+		reqs = append(reqs, ethRequest{Method: req.Method, Body: req.Params[0], Signature: r.Header.Get(rpc.FlashbotsSignatureHeader), RawBody: raw})
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"` + bundleHash + `"}}`))
+	}))
+	defer server.Close()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := newEthBuilder(Config{Brand: Flashbots, URL: server.URL, SignerKey: hexutil.Encode(crypto.FromECDSA(key))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eb := b.(*ethBuilder)
+
+	args := &types.SendBundleArgs{Txs: []hexutil.Bytes{{0x01}}, MaxBlockNumber: 124}
+	id, err := eb.SendBundle(context.Background(), args, 25)
+	if err != nil || id != bundleHash {
+		t.Fatalf("SendBundle() = %s, %v, want %s", id, err, bundleHash)
+	}
+
+	if err := eb.ResendBundle(context.Background(), id, args, 102); err != nil {
+		t.Fatalf("ResendBundle() = %v", err)
+	}
+
+	if err := eb.CancelBundle(context.Background(), id); err != nil {
+		t.Fatalf("CancelBundle() = %v", err)
+	}
+
+	if err := eb.CancelBundle(context.Background(), id); !errors.Is(err, ErrUnknownBundle) {
+		t.Fatalf("CancelBundle() of a cancelled bundle = %v, want ErrUnknownBundle", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(reqs) != 3 {
+		t.Fatalf("requests = %d, want send, resend and cancel", len(reqs))
+	}
+
+	send, resend, cancel := reqs[0], reqs[1], reqs[2]
+	if send.Method != EthSendBundleMethod || send.Body["blockNumber"] != "0x64" {
+		t.Fatalf("send = %s %v, want %s from block 0x64", send.Method, send.Body, EthSendBundleMethod)
+	}
+
+	uuid, _ := send.Body["replacementUuid"].(string)
+	if uuid == "" {
+		t.Fatal("send has no replacementUuid")
+	}
+
+	if resend.Body["replacementUuid"] != uuid || resend.Body["blockNumber"] != "0x66" {
+		t.Fatalf("resend = %v, want the uuid %s at block 0x66", resend.Body, uuid)
+	}
+
+	if cancel.Method != EthCancelBundleMethod || cancel.Body["replacementUuid"] != uuid {
+		t.Fatalf("cancel = %s %v, want %s of %s", cancel.Method, cancel.Body, EthCancelBundleMethod, uuid)
+	}
+
+	// every request carries the flashbots signature of its exact body
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	for _, req := range reqs {
+		address, sig, _ := strings.Cut(req.Signature, ":")
+		sigByte, err := hexutil.Decode(sig)
+		if address != signer.Hex() || err != nil {
+			t.Fatalf("%s signature = %q, want one by %s", req.Method, req.Signature, signer.Hex())
+		}
+
+		pub, err := crypto.SigToPub(accounts.TextHash([]byte(hexutil.Encode(crypto.Keccak256(req.RawBody)))), sigByte)
+		if err != nil || crypto.PubkeyToAddress(*pub) != signer {
+			t.Fatalf("%s signature does not match the body", req.Method)
+		}
+	}
+}
+
+func TestEthBuilderUnknownBundle(t *testing.T) {
+	b, err := newEthBuilder(Config{Brand: Titan, URL: "https://rpc.titanbuilder.xyz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eb := b.(*ethBuilder)
+	if err := eb.ResendBundle(context.Background(), "0x01", &types.SendBundleArgs{}, 1); !errors.Is(err, ErrUnknownBundle) {
+		t.Fatalf("ResendBundle() = %v, want ErrUnknownBundle", err)
+	}
+
+	if err := eb.CancelBundle(context.Background(), "0x01"); !errors.Is(err, ErrInvalidBundleID) {
+		t.Fatalf("CancelBundle() = %v, want ErrInvalidBundleID", err)
+	}
+}
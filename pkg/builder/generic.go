@@ -0,0 +1,244 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+// Generic is the brand of the builders described by GenericConfig instead of a dedicated adapter.
+const Generic Brand = "generic"
+
+const GenericMethod = "eth_sendBundle"
+
+type BlockNumberFormat string
+
+const (
+	BlockNumberHex     BlockNumberFormat = "hex"     // "0x2a"
+	BlockNumberDecimal BlockNumberFormat = "decimal" // 42
+)
+
+type BlockTarget string
+
+const (
+	BlockTargetNext BlockTarget = "next" // the first block of the bundle window
+	BlockTargetMax  BlockTarget = "max"  // the last block of the bundle window
+)
+
+type AuthScheme string
+
+const (
+	AuthRaw    AuthScheme = "raw"    // the key as is, the default when a key is set
+	AuthBearer AuthScheme = "bearer" // "Bearer <key>"
+	AuthNone   AuthScheme = "none"
+)
+
+// GenericFields names the json fields of the bundle, an empty name leaves the field out
+// except for Txs. The zero value takes the flashbots names.
+type GenericFields struct {
+	Txs               string
+	BlockNumber       string
+	MinTimestamp      string
+	MaxTimestamp      string
+	RevertingTxHashes string
+}
+
+// GenericConfig describes an eth_sendBundle variant, zero fields take the flashbots conventions:
+// eth_sendBundle with a hex next block number, timestamps and the key in the Authorization header.
+type GenericConfig struct {
+	Name        string // reported as the brand of the builder, defaults to generic
+	Method      string
+	Fields      GenericFields
+	BlockNumber BlockNumberFormat
+	BlockTarget BlockTarget
+	// OmitTimestamps leaves minTimestamp and maxTimestamp out for the builders rejecting them
+	OmitTimestamps bool
+	Auth           AuthScheme
+	AuthHeader     string                 // defaults to Authorization
+	Extra          map[string]interface{} // static fields added to the bundle, e.g. ignoreBlockNumber
+}
+
+func (c *GenericConfig) Validate() error {
+	switch c.BlockNumber {
+	case "", BlockNumberHex, BlockNumberDecimal:
+	default:
+		return fmt.Errorf("unknown block number format %q", c.BlockNumber)
+	}
+
+	switch c.BlockTarget {
+	case "", BlockTargetNext, BlockTargetMax:
+	default:
+		return fmt.Errorf("unknown block target %q", c.BlockTarget)
+	}
+
+	switch c.Auth {
+	case "", AuthRaw, AuthBearer, AuthNone:
+	default:
+		return fmt.Errorf("unknown auth scheme %q", c.Auth)
+	}
+
+	return nil
+}
+
+func (c *GenericConfig) applyDefaults() {
+	if c.Name == "" {
+		c.Name = string(Generic)
+	}
+
+	if c.Method == "" {
+		c.Method = GenericMethod
+	}
+
+	if c.Fields == (GenericFields{}) {
+		c.Fields = GenericFields{
+			Txs:               "txs",
+			BlockNumber:       "blockNumber",
+			MinTimestamp:      "minTimestamp",
+			MaxTimestamp:      "maxTimestamp",
+			RevertingTxHashes: "revertingTxHashes",
+		}
+	}
+
+	if c.Fields.Txs == "" {
+		c.Fields.Txs = "txs"
+	}
+
+	if c.BlockNumber == "" {
+		c.BlockNumber = BlockNumberHex
+	}
+
+	if c.BlockTarget == "" {
+		c.BlockTarget = BlockTargetNext
+	}
+
+	if c.Auth == "" {
+		c.Auth = AuthRaw
+	}
+
+	if c.AuthHeader == "" {
+		c.AuthHeader = "Authorization"
+	}
+}
+
+// authHeader returns the header carrying the key in the auth scheme, nil without a key or with
+// AuthNone. The defaults must be applied.
+func (c GenericConfig) authHeader(key string) map[string]string {
+	if key == "" || c.Auth == AuthNone {
+		return nil
+	}
+
+	if c.Auth == AuthBearer {
+		key = "Bearer " + key
+	}
+
+	return map[string]string{c.AuthHeader: key}
+}
+
+func newGeneric(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	gc := GenericConfig{}
+	if cfg.Generic != nil {
+		gc = *cfg.Generic
+	}
+
+	if err := gc.Validate(); err != nil {
+		return nil, err
+	}
+	gc.applyDefaults()
+
+	return &generic{
+		cfg:     gc,
+		key:     cfg.Key,
+		builder: base,
+	}, nil
+}
+
+type generic struct {
+	cfg GenericConfig
+	key string
+	*builder
+}
+
+func (b *generic) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) (BundleID, error) {
+	req, err := b.newRequest(args, bundleLifeNumber)
+	if err != nil {
+		log.Error("failed to create generic jsonrpc request", "builder", b.cfg.Name, "err", err)
+		return "", err
+	}
+
+	var options []rpc.CallOption
+	if header := b.cfg.authHeader(b.key); header != nil {
+		options = append(options, rpc.WithHeader(header))
+	}
+
+	result, err := b.call(ctx, req, options...)
+	if err != nil {
+		log.Error("failed to send generic bundle", "builder", b.cfg.Name, "err", err)
+		return "", err
+	}
+
+	return parseBundleID(b.brand, result), nil
+}
+
+func (b *generic) GetBrand() string {
+	return b.cfg.Name
+}
+
+func (b *generic) newRequest(args *types.SendBundleArgs, bundleLifeNumber uint64) (*rpc.JsonrpcRequest, error) {
+	body := make(map[string]interface{}, len(b.cfg.Extra)+5)
+	for k, v := range b.cfg.Extra {
+		body[k] = v
+	}
+
+	body[b.cfg.Fields.Txs] = args.Txs
+
+	if b.cfg.Fields.BlockNumber != "" {
+		blockNumber := args.MaxBlockNumber
+		if b.cfg.BlockTarget == BlockTargetNext {
+			blockNumber = args.MaxBlockNumber - bundleLifeNumber + 1
+		}
+
+		if b.cfg.BlockNumber == BlockNumberHex {
+			body[b.cfg.Fields.BlockNumber] = hexutil.Uint64(blockNumber)
+		} else {
+			body[b.cfg.Fields.BlockNumber] = blockNumber
+		}
+	}
+
+	if !b.cfg.OmitTimestamps {
+		if b.cfg.Fields.MinTimestamp != "" && args.MinTimestamp != nil {
+			body[b.cfg.Fields.MinTimestamp] = *args.MinTimestamp
+		}
+
+		if b.cfg.Fields.MaxTimestamp != "" && args.MaxTimestamp != nil {
+			body[b.cfg.Fields.MaxTimestamp] = *args.MaxTimestamp
+		}
+	}
+
+	if b.cfg.Fields.RevertingTxHashes != "" && len(args.RevertingTxHashes) > 0 {
+		body[b.cfg.Fields.RevertingTxHashes] = args.RevertingTxHashes
+	}
+
+	bodybyte, err := jsoniter.Marshal(body)
+	if err != nil {
+		log.Error("failed to marshal generic body", "builder", b.cfg.Name, "err", err)
+		return nil, err
+	}
+
+	return &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  b.cfg.Method,
+		Params:  []rpc.Param{bodybyte},
+	}, nil
+}
@@ -0,0 +1,116 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestGenericNewRequest(t *testing.T) {
+	minTimestamp, maxTimestamp := uint64(1700000000), uint64(1700000063)
+	reverting := common.HexToHash("0x01")
+	args := &types.SendBundleArgs{
+		Txs:               []hexutil.Bytes{{0x01, 0x02}},
+		MaxBlockNumber:    120,
+		MinTimestamp:      &minTimestamp,
+		MaxTimestamp:      &maxTimestamp,
+		RevertingTxHashes: []common.Hash{reverting},
+	}
+
+	tests := []struct {
+		name   string
+		cfg    *GenericConfig
+		method string
+		want   string
+	}{
+		{"flashbots defaults", nil, GenericMethod, `{"txs":["0x0102"],"blockNumber":"0x65","minTimestamp":1700000000,
+			"maxTimestamp":1700000063,"revertingTxHashes":["` + reverting.Hex() + `"]}`},
+		{"renamed fields", &GenericConfig{Method: "mev_sendBundle", Fields: GenericFields{Txs: "transactions", BlockNumber: "block"}},
+			"mev_sendBundle", `{"transactions":["0x0102"],"block":"0x65"}`},
+		{"decimal max block", &GenericConfig{BlockNumber: BlockNumberDecimal, BlockTarget: BlockTargetMax}, GenericMethod,
+			`{"txs":["0x0102"],"blockNumber":120,"minTimestamp":1700000000,"maxTimestamp":1700000063,"revertingTxHashes":["` + reverting.Hex() + `"]}`},
+		{"omit timestamps", &GenericConfig{OmitTimestamps: true}, GenericMethod,
+			`{"txs":["0x0102"],"blockNumber":"0x65","revertingTxHashes":["` + reverting.Hex() + `"]}`},
+		{"txs only", &GenericConfig{Fields: GenericFields{Txs: "txs"}}, GenericMethod, `{"txs":["0x0102"]}`},
+		{"extra fields", &GenericConfig{Fields: GenericFields{Txs: "txs"}, Extra: map[string]interface{}{"ignoreBlockNumber": true}},
+			GenericMethod, `{"txs":["0x0102"],"ignoreBlockNumber":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newGeneric(Config{Brand: Generic, URL: "https://builder.example.com", Generic: tt.cfg})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := b.(*generic).newRequest(args, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if req.Method != tt.method || len(req.Params) != 1 {
+				t.Fatalf("newRequest() = %s with %d params, want %s with 1", req.Method, len(req.Params), tt.method)
+			}
+
+			var got, want map[string]interface{}
+			if err := json.Unmarshal(req.Params[0], &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("newRequest() body = %s, want %s", req.Params[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestGenericAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *GenericConfig
+		key    string
+		header string
+		want   string
+	}{
+		{"raw key", nil, "key", "Authorization", "key"},
+		{"bearer key", &GenericConfig{Auth: AuthBearer}, "key", "Authorization", "Bearer key"},
+		{"custom header", &GenericConfig{AuthHeader: "X-Api-Key"}, "key", "X-Api-Key", "key"},
+		{"no auth", &GenericConfig{Auth: AuthNone}, "key", "Authorization", ""},
+		{"no key", nil, "", "Authorization", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(tt.header)
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"bundle-1"}`))
+			}))
+			defer server.Close()
+
+			b, err := newGeneric(Config{Brand: Generic, URL: server.URL, Key: tt.key, Generic: tt.cfg})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			id, err := b.SendBundle(context.Background(), &types.SendBundleArgs{MaxBlockNumber: 100}, 20)
+			if err != nil || id != "bundle-1" {
+				t.Fatalf("SendBundle() = %s, %v, want bundle-1", id, err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,4 +18,34 @@ var (
 		Subsystem: system,
 		Name:      "error",
 	}, []string{"url"})
+
+	// EndpointWinCounter counts the calls answered by each endpoint of a builder with several endpoints
+	EndpointWinCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "endpoint_win",
+	}, []string{"brand", "url"})
+
+	// EndpointDuplicateCounter counts the successful answers arriving after the call was already answered
+	EndpointDuplicateCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "endpoint_duplicate",
+	}, []string{"brand", "url"})
+
+	EndpointLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "endpoint_latency_seconds",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"url"})
+
+	// EndpointTTFB is the time to the first byte of the answers, conn is "new" or "reused" to
+	// compare cold connections with the ones kept warm
+	EndpointTTFB = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "endpoint_ttfb_seconds",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"url", "conn"})
 )
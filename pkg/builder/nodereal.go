@@ -2,44 +2,84 @@ package builder
 
 import (
 	"context"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
 )
 
-func newNodeReal(cfg Config) Builder {
-	client, err := ethclient.Dial(cfg.URL)
+func newNodeReal(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
 	if err != nil {
-		log.Crit("failed to dial ethclient", "url", cfg.URL, "err", err)
-		return nil
+		return nil, err
 	}
 
-	return &nodeReal{
-		builder: &builder{
-			url:   cfg.URL,
-			brand: cfg.Brand,
-		},
-		ethclient: client,
-	}
+	return &nodeReal{builder: base}, nil
 }
 
 type nodeReal struct {
 	*builder
-	ethclient *ethclient.Client
 }
 
-func (b *nodeReal) SendBundle(ctx context.Context, args *types.SendBundleArgs, _ uint64) error {
-	bundlehash, err := b.ethclient.SendBundle(ctx, *args)
+func (b *nodeReal) SendBundle(ctx context.Context, args *types.SendBundleArgs, _ uint64) (BundleID, error) {
+	argsbyte, err := jsoniter.Marshal(args)
+	if err != nil {
+		log.Error("failed to marshal nodereal bundle args", "err", err)
+		return "", err
+	}
+
+	req := &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  NodeRealSendBundleMethod,
+		Params:  []rpc.Param{argsbyte},
+	}
+
+	result, err := b.call(ctx, req)
 	if err != nil {
 		log.Error("failed to send bundle", "url", b.url, "err", err)
-		return err
+		return "", err
+	}
+
+	return parseBundleID(b.brand, result), nil
+}
+
+func (b *nodeReal) BundlePrice(ctx context.Context) (*big.Int, error) {
+	req := &rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  NodeRealBundlePriceMethod,
+		Params:  rpc.Params{},
+	}
+
+	result, err := b.call(ctx, req)
+	if err != nil {
+		log.Error("failed to query bundle price", "url", b.url, "err", err)
+		return nil, err
+	}
+
+	var price *hexutil.Big
+	if err := jsoniter.Unmarshal(result, &price); err != nil {
+		log.Error("invalid bundle price", "url", b.url, "result", string(result), "err", err)
+		return nil, err
+	}
+
+	if price == nil {
+		return nil, ErrNoBundlePrice
 	}
 
-	log.Info("send bundle success", "url", b.url, "bundle_hash", bundlehash)
-	return nil
+	return price.ToInt(), nil
 }
 
 func (b *nodeReal) GetBrand() string {
 	return string(b.brand)
 }
+
+const (
+	NodeRealSendBundleMethod  = "eth_sendBundle"
+	NodeRealBundlePriceMethod = "eth_bundlePrice"
+)
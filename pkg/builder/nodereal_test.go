@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNodeRealBundlePrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		body    string
+		want    int64
+		wantErr bool
+	}{
+		{"price", http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":"0x3b9aca00"}`, 1000000000, false},
+		{"rpc error", http.StatusOK, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`, 0, true},
+		{"http error", http.StatusBadGateway, ``, 0, true},
+		{"null price", http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			b, err := newNodeReal(Config{Brand: Nodereal, URL: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			price, err := b.(BundlePricer).BundlePrice(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BundlePrice() = %v, want an error", price)
+				}
+				return
+			}
+
+			if err != nil || price.Int64() != tt.want {
+				t.Fatalf("BundlePrice() = %v, %v, want %d", price, err, tt.want)
+			}
+		})
+	}
+}
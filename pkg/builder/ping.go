@@ -0,0 +1,102 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+const PingMethod = "web3_clientVersion"
+
+// Ping measures the round trip of a cheap jsonrpc call to the builder, any http answer below 500
+// counts as reachable since builders often reject methods other than their bundle ones.
+func Ping(ctx context.Context, cfg Config) (time.Duration, error) {
+	if strings.HasPrefix(cfg.URL, "ws://") || strings.HasPrefix(cfg.URL, "wss://") {
+		return pingWS(ctx, cfg)
+	}
+
+	reqByte, err := jsoniter.Marshal(&rpc.JsonrpcRequest{
+		ID:      1,
+		Version: "2.0",
+		Method:  PingMethod,
+		Params:  rpc.Params{},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(reqByte))
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq.Header.Set("Content-Type", gin.MIMEJSON)
+	for k, v := range authHeader(cfg) {
+		httpReq.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	httpResp, err := rpc.HTTPClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResp.Body.Close()
+
+	latency := time.Since(start)
+	// read the answer to the end so that the connection goes back to the pool for the next call
+	_, _ = io.Copy(io.Discard, httpResp.Body)
+	if httpResp.StatusCode >= http.StatusInternalServerError {
+		return latency, fmt.Errorf("builder answered with code: %d", httpResp.StatusCode)
+	}
+
+	return latency, nil
+}
+
+// pingWS measures the websocket handshake.
+func pingWS(ctx context.Context, cfg Config) (time.Duration, error) {
+	header := http.Header{}
+	for k, v := range authHeader(cfg) {
+		header.Set(k, v)
+	}
+
+	start := time.Now()
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, cfg.URL, header)
+	if err != nil {
+		return 0, err
+	}
+
+	latency := time.Since(start)
+	_ = conn.Close()
+
+	return latency, nil
+}
+
+// authHeader returns the header the builder authenticates its requests with, the generic builders
+// follow their auth scheme and the others send the key as is.
+func authHeader(cfg Config) map[string]string {
+	if cfg.Brand == Generic {
+		gc := GenericConfig{}
+		if cfg.Generic != nil {
+			gc = *cfg.Generic
+		}
+		gc.applyDefaults()
+
+		return gc.authHeader(cfg.Key)
+	}
+
+	if cfg.Key == "" {
+		return nil
+	}
+
+	return map[string]string{"Authorization": cfg.Key}
+}
@@ -0,0 +1,76 @@
+package builder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		brand   Brand
+		generic *GenericConfig
+		code    int
+		header  string
+		want    string
+		wantErr bool
+	}{
+		{"raw key", Nodereal, nil, http.StatusOK, "Authorization", "key", false},
+		{"generic bearer", Generic, &GenericConfig{Auth: AuthBearer}, http.StatusOK, "Authorization", "Bearer key", false},
+		{"generic custom header", Generic, &GenericConfig{AuthHeader: "X-Api-Key"}, http.StatusOK, "X-Api-Key", "key", false},
+		{"generic no auth", Generic, &GenericConfig{Auth: AuthNone}, http.StatusOK, "Authorization", "", false},
+		{"method rejected", Nodereal, nil, http.StatusMethodNotAllowed, "Authorization", "key", false},
+		{"server error", Nodereal, nil, http.StatusBadGateway, "Authorization", "key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(tt.header)
+				w.WriteHeader(tt.code)
+			}))
+			defer server.Close()
+
+			_, err := Ping(context.Background(), Config{Brand: tt.brand, URL: server.URL, Key: "key", Generic: tt.generic})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ping() = %v, want error %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepWarm(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"builder"}`))
+	}))
+	defer server.Close()
+
+	b, err := newNodeReal(Config{Brand: Nodereal, URL: server.URL, KeepAlive: Duration(10 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	KeepWarm(ctx, []Builder{b})
+
+	deadline := time.Now().Add(time.Second)
+	for pings.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	if n := pings.Load(); n < 3 {
+		t.Fatalf("pings = %d, want the warm up and the keep alive requests", n)
+	}
+}
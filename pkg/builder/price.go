@@ -0,0 +1,88 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var ErrNoBundlePrice = errors.New("builder returned no bundle price")
+
+// BundlePricer is implemented by the builders publishing a minimum gas price for bundles,
+// bundles priced below it are silently ignored by the builder.
+type BundlePricer interface {
+	BundlePrice(ctx context.Context) (*big.Int, error)
+}
+
+// PriceCache caches the bundle price floor of every builder implementing BundlePricer.
+type PriceCache struct {
+	mu     sync.RWMutex
+	floors map[Builder]*big.Int
+}
+
+func NewPriceCache() *PriceCache {
+	return &PriceCache{floors: make(map[Builder]*big.Int)}
+}
+
+// Refresh queries the floors of the given builders, a builder keeps its last known floor when the query fails.
+func (c *PriceCache) Refresh(ctx context.Context, builders []Builder) {
+	for _, b := range builders {
+		pricer, ok := b.(BundlePricer)
+		if !ok {
+			continue
+		}
+
+		price, err := pricer.BundlePrice(ctx)
+		if err != nil {
+			log.Error("failed to query bundle price", "builder", b.GetBrand(), "err", err)
+			continue
+		}
+
+		c.mu.Lock()
+		c.floors[b] = price
+		c.mu.Unlock()
+	}
+}
+
+// Retain drops the floors of the builders not in the list, so the floors of replaced builders are not reported.
+func (c *PriceCache) Retain(builders []Builder) {
+	keep := make(map[Builder]struct{}, len(builders))
+	for _, b := range builders {
+		keep[b] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for b := range c.floors {
+		if _, ok := keep[b]; !ok {
+			delete(c.floors, b)
+		}
+	}
+}
+
+// Floor returns the cached floor of the builder, ok is false if the builder has none.
+func (c *PriceCache) Floor(b Builder) (floor *big.Int, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	floor, ok = c.floors[b]
+	return
+}
+
+// Floors returns the cached floors keyed by the ID of the builder, the builders of a brand have
+// their own floor.
+func (c *PriceCache) Floors() map[string]*big.Int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	floors := make(map[string]*big.Int, len(c.floors))
+	for b, floor := range c.floors {
+		floors[ID(b)] = new(big.Int).Set(floor)
+	}
+
+	return floors
+}
@@ -14,33 +14,38 @@ import (
 
 const PuissantMethod = "eth_sendPuissant"
 
-func newPuissant(cfg Config) Builder {
-	return &puissant{
-		builder: &builder{
-			url:   cfg.URL,
-			brand: cfg.Brand,
-		},
+// Deprecated: 48 Club retired eth_sendPuissant, use the 48club brand.
+func newPuissant(cfg Config) (Builder, error) {
+	log.Warn("the puissant builder brand is deprecated, configure the 48club brand instead", "url", cfg.URL)
+
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	return &puissant{
+		builder: base,
+	}, nil
 }
 
 type puissant struct {
 	*builder
 }
 
-func (b *puissant) SendBundle(ctx context.Context, args *types.SendBundleArgs, _ uint64) error {
+func (b *puissant) SendBundle(ctx context.Context, args *types.SendBundleArgs, _ uint64) (BundleID, error) {
 	req, err := newPuissantRequest(args)
 	if err != nil {
 		log.Error("failed to create puissant jsonrpc request", "err", err)
-		return err
+		return "", err
 	}
 
-	err = SendBundleCall(ctx, b.url, req)
+	result, err := b.call(ctx, req)
 	if err != nil {
 		log.Error("failed to send puissant bundle", "err", err)
-		return err
+		return "", err
 	}
 
-	return nil
+	return parseBundleID(b.brand, result), nil
 }
 
 func (b *puissant) GetBrand() string {
@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory creates the builder of a brand from its config.
+type Factory func(cfg Config) (Builder, error)
+
+var ErrUnknownBrand = errors.New("unknown builder brand")
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[Brand]Factory)
+)
+
+func init() {
+	Register(Nodereal, newNodeReal)
+	Register(Puissant, newPuissant)
+	Register(Txboost, newTxboost)
+	Register(Bloxroute, newBloxroute)
+	Register(Blockrazor, newBlockrazor)
+	Register(Club48, newClub48)
+	Register(Generic, newGeneric)
+	Register(Flashbots, newEthBuilder)
+	Register(Beaverbuild, newEthBuilder)
+	Register(Titan, newEthBuilder)
+	Register(Rsync, newEthBuilder)
+}
+
+// Register makes a brand available to New, registering a known brand replaces its adapter.
+// Applications register their own adapters in an init function or before loading the config.
+func Register(brand Brand, factory Factory) {
+	if factory == nil {
+		panic("builder: nil factory for brand " + string(brand))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[brand] = factory
+}
+
+// Brands returns the registered brands in order.
+func Brands() []Brand {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	brands := make([]Brand, 0, len(registry))
+	for brand := range registry {
+		brands = append(brands, brand)
+	}
+
+	sort.Slice(brands, func(i, j int) bool { return brands[i] < brands[j] })
+	return brands
+}
+
+// Valid reports whether the brand is registered.
+func (b Brand) Valid() bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	_, ok := registry[b]
+	return ok
+}
+
+func New(cfg Config) (Builder, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Brand]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBrand, cfg.Brand)
+	}
+
+	if cfg.Region == RegionAuto {
+		cfg = pickRegion(cfg)
+	}
+
+	return factory(cfg)
+}
+
+// NewAll creates the builders of the configs, it fails on the first invalid one.
+func NewAll(configs []Config) ([]Builder, error) {
+	pickRegions(configs)
+
+	builders := make([]Builder, 0, len(configs))
+	for _, cfg := range configs {
+		b, err := New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("builder %s %s: %w", cfg.Brand, cfg.URL, err)
+		}
+
+		builders = append(builders, b)
+	}
+
+	return builders, nil
+}
@@ -0,0 +1,33 @@
+package builder
+
+import (
+	"context"
+)
+
+type BundleState string
+
+const (
+	BundleUnknown         BundleState = "unknown"
+	BundlePending         BundleState = "pending"
+	BundleSimulatedFailed BundleState = "simulated-failed"
+	BundleIncluded        BundleState = "included"
+	BundleExpired         BundleState = "expired"
+)
+
+// Finished reports whether the state will not change any more.
+func (s BundleState) Finished() bool {
+	return s == BundleSimulatedFailed || s == BundleIncluded || s == BundleExpired
+}
+
+type BundleStatus struct {
+	State       BundleState
+	BlockNumber uint64 // the block including the bundle, only set when State is BundleIncluded
+	Reason      string
+}
+
+// BundleStatusQuerier is implemented by the builders which offer bundle status lookups, none of the
+// builders of the package documents one, adapters registered by applications may. Without one the
+// status of a tx follows its receipt, and only a failed simulation of the sender bans in the guard.
+type BundleStatusQuerier interface {
+	BundleStatus(ctx context.Context, id BundleID) (*BundleStatus, error)
+}
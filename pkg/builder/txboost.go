@@ -15,14 +15,16 @@ import (
 
 const TxboostMethod = "eth_sendBundle"
 
-func newTxboost(cfg Config) Builder {
-	return &txboost{
-		key: cfg.Key,
-		builder: &builder{
-			url:   cfg.URL,
-			brand: cfg.Brand,
-		},
+func newTxboost(cfg Config) (Builder, error) {
+	base, err := newBuilder(cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	return &txboost{
+		key:     cfg.Key,
+		builder: base,
+	}, nil
 }
 
 type txboost struct {
@@ -30,24 +32,24 @@ type txboost struct {
 	*builder
 }
 
-func (b *txboost) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) error {
+func (b *txboost) SendBundle(ctx context.Context, args *types.SendBundleArgs, bundleLifeNumber uint64) (BundleID, error) {
 	req, err := newTxboostRequest(args, bundleLifeNumber)
 	if err != nil {
 		log.Error("failed to create txboost jsonrpc request", "err", err)
-		return err
+		return "", err
 	}
 
 	opt := rpc.WithHeader(map[string]string{
 		"Authorization": b.key,
 	})
 
-	err = SendBundleCall(ctx, b.url, req, opt)
+	result, err := b.call(ctx, req, opt)
 	if err != nil {
 		log.Error("failed to send txboost bundle", "err", err)
-		return err
+		return "", err
 	}
 
-	return nil
+	return parseBundleID(b.brand, result), nil
 }
 
 func (b *txboost) GetBrand() string {
@@ -0,0 +1,100 @@
+package builder
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+// warmTimeout bounds a warm up request, it is larger than the dial timeout so that a cold
+// connection can be set up.
+const warmTimeout = 3 * time.Second
+
+// DefaultKeepAlive is the interval of the requests keeping a connection open when the builder sets
+// no KeepAlive, below the 90s idle timeout of rpc.HTTPClient.
+const DefaultKeepAlive = 30 * time.Second
+
+// warmable is implemented by the builders created on the base builder of the package.
+type warmable interface {
+	warmConfig() Config
+}
+
+func (b *builder) warmConfig() Config {
+	return b.cfg
+}
+
+// KeepWarm opens a connection to every http endpoint of the builders now, so that the first bundle
+// does not pay the dns, tcp and tls setup, then keeps it open with a request every KeepAlive of the
+// builder, DefaultKeepAlive when unset, until ctx is done. Websocket endpoints hold their own
+// connection and are skipped, as are the builders of other packages.
+func KeepWarm(ctx context.Context, builders []Builder) {
+	for _, b := range builders {
+		w, ok := b.(warmable)
+		if !ok {
+			continue
+		}
+
+		cfg := w.warmConfig()
+		for _, endpoint := range cfg.Endpoints() {
+			if strings.HasPrefix(endpoint, "ws://") || strings.HasPrefix(endpoint, "wss://") {
+				continue
+			}
+
+			endpointCfg := cfg
+			endpointCfg.URL, endpointCfg.URLs = endpoint, nil
+			go keepWarm(ctx, endpointCfg)
+		}
+	}
+}
+
+func keepWarm(ctx context.Context, cfg Config) {
+	warmUp(ctx, cfg)
+
+	interval := time.Duration(cfg.KeepAlive)
+	if interval <= 0 {
+		interval = DefaultKeepAlive
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			warmUp(ctx, cfg)
+		}
+	}
+}
+
+// warmUp refreshes the pinned addresses of the endpoint and sends it a ping, which leaves an idle
+// connection in the pool of rpc.HTTPClient for the next bundle.
+func warmUp(ctx context.Context, cfg Config) {
+	ctx, cancel := context.WithTimeout(ctx, warmTimeout)
+	defer cancel()
+
+	if cfg.PinDNS {
+		u, err := url.Parse(cfg.URL)
+		if err == nil {
+			err = rpc.PinHost(ctx, u.Hostname())
+		}
+
+		if err != nil {
+			log.Warn("failed to pin builder endpoint dns, keep the previous addresses", "brand", cfg.Brand, "url", cfg.URL, "err", err)
+		}
+	}
+
+	latency, err := Ping(ctx, cfg)
+	if err != nil {
+		log.Warn("failed to warm up builder endpoint", "brand", cfg.Brand, "url", cfg.URL, "err", err)
+		return
+	}
+
+	log.Debug("builder endpoint warm", "brand", cfg.Brand, "url", cfg.URL, "latency", latency)
+}
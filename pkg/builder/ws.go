@@ -0,0 +1,245 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+var (
+	errWSClosed = errors.New("websocket connection closed")
+	// errWSSigned is returned for builders with a SignerKey, the signature is an http header and a
+	// websocket message has none
+	errWSSigned = errors.New("signed requests need an http endpoint")
+)
+
+// wsWriteTimeout bounds a write when the context of the call has no earlier deadline.
+const wsWriteTimeout = 5 * time.Second
+
+type wsRequest struct {
+	ID      uint64          `json:"id"`
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// wsClient sends jsonrpc calls over a persistent websocket connection, the connection is dialed
+// on the first call and again on the call following a failure. The headers of the call options
+// are sent when dialing.
+type wsClient struct {
+	url string
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	nextID  uint64
+	pending map[uint64]chan rpc.JsonrpcResponse
+}
+
+func newWSClient(url string) *wsClient {
+	return &wsClient{
+		url:     url,
+		pending: make(map[uint64]chan rpc.JsonrpcResponse),
+	}
+}
+
+// Call sends the jsonrpc request, its id is replaced by one unique on the connection. It returns
+// the raw result of the call, a jsonrpc error is returned as *rpc.JsonrpcError.
+func (c *wsClient) Call(ctx context.Context, req interface{}, options ...rpc.CallOption) (json.RawMessage, error) {
+	opt := &rpc.CallOptions{Header: map[string]string{}}
+	opt.ApplyOptions(options...)
+
+	if opt.Signer != nil {
+		log.Error("failed to sign websocket request", "url", c.url, "err", errWSSigned)
+		return nil, errWSSigned
+	}
+
+	reqByte, err := jsoniter.Marshal(req)
+	if err != nil {
+		log.Error("failed to marshal jsonrpc request body", "url", c.url, "err", err)
+		return nil, err
+	}
+
+	call := wsRequest{}
+	if err := jsoniter.Unmarshal(reqByte, &call); err != nil {
+		log.Error("failed to read jsonrpc request body", "url", c.url, "err", err)
+		return nil, err
+	}
+
+	// the sender calls without a deadline, bound the call as the http client bounds its requests
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rpc.HTTPClient.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	conn, dialed, err := c.connect(ctx, opt.Header)
+	if err != nil {
+		ErrorCounter.WithLabelValues(c.url).Inc()
+
+		log.Error("failed to dial websocket", "url", c.url, "err", err)
+		return nil, err
+	}
+
+	respCh := make(chan rpc.JsonrpcResponse, 1)
+
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = respCh
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > wsWriteTimeout {
+		deadline = time.Now().Add(wsWriteTimeout)
+	}
+
+	_ = conn.SetWriteDeadline(deadline)
+	call.ID, call.Version = id, "2.0"
+	err = conn.WriteJSON(&call)
+	if err != nil {
+		delete(c.pending, id)
+		c.closeLocked(conn)
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		ErrorCounter.WithLabelValues(c.url).Inc()
+
+		log.Error("failed to write websocket request", "url", c.url, "err", err)
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+
+		ErrorCounter.WithLabelValues(c.url).Inc()
+
+		log.Error("failed to wait for websocket response", "url", c.url, "err", ctx.Err())
+		return nil, ctx.Err()
+	case resp, ok := <-respCh:
+		if !ok {
+			ErrorCounter.WithLabelValues(c.url).Inc()
+			return nil, errWSClosed
+		}
+
+		// a message arrives whole, its first byte is the answer
+		elapsed := time.Since(start)
+		EndpointLatency.WithLabelValues(c.url).Observe(elapsed.Seconds())
+		connLabel := "reused"
+		if dialed {
+			connLabel = "new"
+		}
+		EndpointTTFB.WithLabelValues(c.url, connLabel).Observe(elapsed.Seconds())
+
+		if resp.Error != nil {
+			ErrorCounter.WithLabelValues(c.url).Inc()
+
+			jrError := &rpc.JsonrpcError{}
+			if err := jsoniter.Unmarshal(*resp.Error, jrError); err != nil {
+				log.Error("failed to unmarshal resp.Error", "url", c.url, "err", err)
+				return nil, err
+			}
+
+			log.Error("websocket response error", "url", c.url, "code", jrError.Code, "message", jrError.Message)
+			return nil, jrError
+		}
+
+		log.Debug("websocket call success", "url", c.url, "result", string(resp.Result))
+		return resp.Result, nil
+	}
+}
+
+// connect returns the open connection or dials a new one, the dial runs without holding c.mu so
+// that a slow handshake does not block the calls and the read loop. dialed is true for a new one.
+func (c *wsClient) connect(ctx context.Context, header map[string]string) (conn *websocket.Conn, dialed bool, err error) {
+	c.mu.Lock()
+	conn = c.conn
+	c.mu.Unlock()
+
+	if conn != nil {
+		return conn, false, nil
+	}
+
+	dialHeader := make(http.Header, len(header))
+	for k, v := range header {
+		dialHeader.Set(k, v)
+	}
+
+	newConn, _, err := websocket.DefaultDialer.DialContext(ctx, c.url, dialHeader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// another call dialed meanwhile, keep its connection
+	if c.conn != nil {
+		_ = newConn.Close()
+		return c.conn, false, nil
+	}
+
+	c.conn = newConn
+	go c.readLoop(newConn)
+
+	return newConn, true, nil
+}
+
+func (c *wsClient) readLoop(conn *websocket.Conn) {
+	for {
+		resp := rpc.JsonrpcResponse{}
+		if err := conn.ReadJSON(&resp); err != nil {
+			log.Warn("websocket connection lost", "url", c.url, "err", err)
+
+			c.mu.Lock()
+			c.closeLocked(conn)
+			c.mu.Unlock()
+			return
+		}
+
+		c.mu.Lock()
+		respCh, ok := c.pending[resp.ID]
+		delete(c.pending, resp.ID)
+		c.mu.Unlock()
+
+		if ok {
+			respCh <- resp
+		}
+	}
+}
+
+// Close drops the connection, the next call dials again.
+func (c *wsClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeLocked(c.conn)
+	return nil
+}
+
+// closeLocked drops the connection and fails its pending calls, c.mu must be held.
+func (c *wsClient) closeLocked(conn *websocket.Conn) {
+	if conn == nil || c.conn != conn {
+		return
+	}
+
+	_ = conn.Close()
+	c.conn = nil
+
+	for id, respCh := range c.pending {
+		close(respCh)
+		delete(c.pending, id)
+	}
+}
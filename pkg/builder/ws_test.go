@@ -0,0 +1,168 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+func TestWSClientCall(t *testing.T) {
+	const hash = "0x6f2b4f1a6f0e8c4a1f3a5b8e0c2d4f6a8b0c2e4f6a8b0d2f4e6a8c0e2f4a6b8d"
+
+	var (
+		mu    sync.Mutex
+		auths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			req := wsRequest{}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+
+			resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": hash}
+			if req.Method != BloxrouteMethod {
+				resp = map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "error": map[string]interface{}{"code": -32601, "message": "method not found"}}
+			}
+
+			if err := conn.WriteJSON(resp); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	header := rpc.WithHeader(map[string]string{"Authorization": "key"})
+
+	tests := []struct {
+		name    string
+		method  string
+		options []rpc.CallOption
+		want    string
+		wantErr bool
+	}{
+		{"result", BloxrouteMethod, []rpc.CallOption{header}, `"` + hash + `"`, false},
+		{"jsonrpc error", "eth_sendBundle", []rpc.CallOption{header}, "", true},
+		{"signed request rejected", BloxrouteMethod, []rpc.CallOption{header, rpc.WithSigner(func([]byte) (map[string]string, error) {
+			return nil, nil
+		})}, "", true},
+	}
+
+	c := newWSClient(url)
+	defer c.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &rpc.JsonrpcRequest{ID: 1, Version: "2.0", Method: tt.method, Params: rpc.Params{}}
+			result, err := c.Call(context.Background(), req, tt.options...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Call() = %s, want an error", result)
+				}
+				return
+			}
+
+			if err != nil || string(result) != tt.want {
+				t.Fatalf("Call() = %s, %v, want %s", result, err, tt.want)
+			}
+		})
+	}
+
+	// concurrent calls share the connection and get their own answers
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := &rpc.JsonrpcRequest{ID: 1, Version: "2.0", Method: BloxrouteMethod, Params: rpc.Params{}}
+			result, err := c.Call(context.Background(), req, header)
+			if err == nil && string(result) != `"`+hash+`"` {
+				err = errors.New("unexpected result " + string(result))
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(auths) != 1 || auths[0] != "key" {
+		t.Fatalf("dials = %q, want a single dial with the Authorization header", auths)
+	}
+}
+
+func TestWSClientClose(t *testing.T) {
+	c := newWSClient("ws://127.0.0.1:0")
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() of a client never dialed = %v", err)
+	}
+
+	result, err := c.Call(context.Background(), &rpc.JsonrpcRequest{Method: BloxrouteMethod, Params: rpc.Params{}})
+	if err == nil {
+		t.Fatalf("Call() to an unreachable url = %s, want an error", result)
+	}
+}
+
+func TestWSClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// read the requests and never answer
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	timeout := rpc.HTTPClient.Timeout
+	rpc.HTTPClient.Timeout = 50 * time.Millisecond
+	defer func() { rpc.HTTPClient.Timeout = timeout }()
+
+	c := newWSClient("ws" + strings.TrimPrefix(server.URL, "http"))
+	defer c.Close()
+
+	result, err := c.Call(context.Background(), &rpc.JsonrpcRequest{ID: 1, Version: "2.0", Method: BloxrouteMethod, Params: rpc.Params{}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Call() to a silent server = %s, %v, want a deadline error", result, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) != 0 {
+		t.Fatalf("pending calls = %d after the timeout, want 0", len(c.pending))
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"time"
+)
+
+type Chain string
+
+const (
+	ChainBSC      Chain = "bsc"
+	ChainEthereum Chain = "ethereum"
+)
+
+// ChainProfile holds the defaults of a chain, fields set in the config file take precedence.
+type ChainProfile struct {
+	ChainID          uint64
+	BlockInterval    time.Duration
+	BundleLifeNumber uint64
+	// Parlia tells whether the chain runs parlia, the validator mapping relies on it
+	Parlia bool
+}
+
+var chainProfiles = map[Chain]ChainProfile{
+	ChainBSC: {
+		ChainID:          56,
+		BlockInterval:    DefaultBlockInterval,
+		BundleLifeNumber: DefaultBundleLifeNumber,
+		Parlia:           true,
+	},
+	// ethereum bundles target a single block and are resent every block, 25 blocks are 5 minutes
+	ChainEthereum: {
+		ChainID:          1,
+		BlockInterval:    12 * time.Second,
+		BundleLifeNumber: 25,
+	},
+}
+
+// Profile returns the profile of the chain, ok is false for an unknown chain.
+func (c Chain) Profile() (profile ChainProfile, ok bool) {
+	profile, ok = chainProfiles[c]
+	return
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/proxy"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+// Config is the file layout shared by the binaries, the example only reads Sender and Builders.
+type Config struct {
+	Chain    Chain // picks the defaults of the chain, bsc when left out
+	Sender   txsender.Config
+	Builders []builder.Config
+	Proxy    proxy.Config
+}
+
+const (
+	DefaultBlockInterval    = 3 * time.Second
+	DefaultBundleLifeNumber = 21
+	DefaultListenAddr       = ":8545"
+)
+
+// Load reads the config file, the format is picked by the extension: .toml, .yaml, .yml or .json.
+// Unknown keys are rejected, then env overrides, secrets, defaults and catalog regions are applied before validation.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		err = decodeTOML(data, cfg)
+	case ".yaml", ".yml":
+		err = decodeYAML(data, cfg)
+	case ".json":
+		err = decodeJSON(data, cfg)
+	default:
+		err = fmt.Errorf("unsupported config format: %q", ext)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	if err := applyEnv(EnvPrefix, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.resolveKeys(); err != nil {
+		return nil, err
+	}
+
+	cfg.applyDefaults()
+
+	if err := cfg.resolveRegions(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func decodeTOML(data []byte, cfg *Config) error {
+	md, err := toml.Decode(string(data), cfg)
+	if err != nil {
+		return err
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+
+		return fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+	}
+
+	return nil
+}
+
+func decodeJSON(data []byte, cfg *Config) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(cfg)
+}
+
+// decodeYAML goes through json so that yaml keys match the field names case-insensitively like
+// the other formats do, and the text unmarshalers of the config types are used.
+func decodeYAML(data []byte, cfg *Config) error {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc == nil {
+		return nil
+	}
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return decodeJSON(jsonData, cfg)
+}
+
+func (c *Config) applyDefaults() {
+	if c.Chain == "" {
+		c.Chain = ChainBSC
+	}
+
+	// an unknown chain is reported by Validate
+	profile, _ := c.Chain.Profile()
+	if c.Sender.BlockInterval == 0 {
+		c.Sender.BlockInterval = txsender.Duration(profile.BlockInterval)
+	}
+
+	if c.Sender.BundleLifeNumber == 0 {
+		c.Sender.BundleLifeNumber = profile.BundleLifeNumber
+	}
+
+	if c.Proxy.ListenAddr == "" {
+		c.Proxy.ListenAddr = DefaultListenAddr
+	}
+}
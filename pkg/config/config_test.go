@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_BLOCKRAZOR_KEY", "env-key")
+
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		check   func(cfg *Config) bool
+		wantErr string
+	}{
+		{"toml with defaults", "config.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+
+[[Builders]]
+Brand = "nodereal"
+URL = "https://bsc-mainnet-builder-us.nodereal.io"
+`, func(cfg *Config) bool {
+			return cfg.Chain == ChainBSC && cfg.Sender.BlockInterval == txsender.Duration(DefaultBlockInterval) &&
+				cfg.Sender.BundleLifeNumber == DefaultBundleLifeNumber && cfg.Proxy.ListenAddr == DefaultListenAddr
+		}, ""},
+		{"yaml keys are case insensitive", "config.yaml", `
+sender:
+  chainurl: https://bsc-dataseed.bnbchain.org
+  blockinterval: 1s
+builders:
+  - brand: nodereal
+    url: https://bsc-mainnet-builder-us.nodereal.io
+`, func(cfg *Config) bool {
+			return cfg.Sender.BlockInterval == txsender.Duration(time.Second) && cfg.Builders[0].Brand == builder.Nodereal
+		}, ""},
+		{"json with ethereum defaults", "config.json", `{
+  "Chain": "ethereum",
+  "Sender": {"ChainURL": "https://ethereum-rpc.publicnode.com"},
+  "Builders": [{"Brand": "titan", "URL": "https://rpc.titanbuilder.xyz"}]
+}`, func(cfg *Config) bool {
+			return cfg.Sender.BlockInterval == txsender.Duration(12*time.Second) && cfg.Sender.BundleLifeNumber == 25
+		}, ""},
+		{"file and env keys", "keys.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+
+[[Builders]]
+Brand = "nodereal"
+URL = "https://bsc-mainnet-builder-us.nodereal.io"
+Key = "file:` + keyFile + `"
+
+[[Builders]]
+Brand = "blockrazor"
+URL = "https://blockrazor-builder-frankfurt.48.club"
+Key = "env:TEST_BLOCKRAZOR_KEY"
+`, func(cfg *Config) bool {
+			return cfg.Builders[0].Key == "file-key" && cfg.Builders[1].Key == "env-key"
+		}, ""},
+		{"catalog region", "region.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+
+[[Builders]]
+Brand = "blockrazor"
+Region = "frankfurt"
+Key = "key"
+`, func(cfg *Config) bool {
+			return cfg.Builders[0].URL == "https://blockrazor-builder-frankfurt.48.club"
+		}, ""},
+		{"unknown region", "unknown-region.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+
+[[Builders]]
+Brand = "blockrazor"
+Region = "antarctica"
+Key = "key"
+`, nil, "unknown region"},
+		{"auto region", "auto-region.toml", `
+Chain = "ethereum"
+
+[Sender]
+ChainURL = "https://ethereum-rpc.publicnode.com"
+
+[[Builders]]
+Brand = "titan"
+Region = "auto"
+`, func(cfg *Config) bool {
+			return len(cfg.Builders[0].Endpoints()) == 4
+		}, ""},
+		{"auto region of a single region brand", "single-region.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+
+[[Builders]]
+Brand = "nodereal"
+Region = "auto"
+`, nil, "single known region"},
+		{"unknown toml key", "unknown.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+ChainUrls = ["https://bsc-dataseed.bnbchain.org"]
+`, nil, "unknown keys: Sender.ChainUrls"},
+		{"unknown json key", "unknown.json", `{"Sender": {"ChainURL": "https://bsc-dataseed.bnbchain.org", "Chain": 56}}`, nil, "unknown field"},
+		{"unsupported format", "config.ini", `ChainURL = x`, nil, "unsupported config format"},
+		{"missing key file", "missing.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+
+[[Builders]]
+Brand = "nodereal"
+URL = "https://bsc-mainnet-builder-us.nodereal.io"
+Key = "file:` + filepath.Join(dir, "missing") + `"
+`, nil, "Builders[0].Key"},
+		{"invalid config", "invalid.toml", `
+[Sender]
+ChainURL = "https://bsc-dataseed.bnbchain.org"
+`, nil, "Builders: no builder configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Load() = %v", err)
+			}
+
+			if !tt.check(cfg) {
+				t.Fatalf("Load() = %+v", cfg)
+			}
+		})
+	}
+}
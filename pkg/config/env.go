@@ -0,0 +1,174 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the env vars overriding the config. The rest of the name is the upper-cased
+// field path joined by underscores, with the index for list entries already present in the file:
+// PRIVATE_TX_SENDER_SENDER_CHAINURL or PRIVATE_TX_SENDER_BUILDERS_0_URL.
+// Lists of scalars take comma separated values, an optional section such as Generic is created
+// when one of its env vars is set.
+const EnvPrefix = "PRIVATE_TX_SENDER"
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	bigIntType          = reflect.TypeOf(big.Int{})
+)
+
+func applyEnv(name string, cfg *Config) error {
+	return applyEnvValue(name, reflect.ValueOf(cfg).Elem())
+}
+
+func applyEnvValue(name string, v reflect.Value) error {
+	if isScalar(v.Type()) {
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			return nil
+		}
+
+		if err := setScalar(v, raw); err != nil {
+			return fmt.Errorf("env var %s: %w", name, err)
+		}
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return nil
+		}
+
+		// an optional section missing from the file is created by its env vars
+		if v.IsNil() {
+			if !hasEnvPrefix(name + "_") {
+				return nil
+			}
+
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return applyEnvValue(name, v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if err := applyEnvValue(name+"_"+strings.ToUpper(field.Name), v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if isScalar(v.Type().Elem()) {
+			raw, ok := os.LookupEnv(name)
+			if !ok {
+				return nil
+			}
+
+			parts := strings.Split(raw, ",")
+			list := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+			for i, part := range parts {
+				if err := setScalar(list.Index(i), strings.TrimSpace(part)); err != nil {
+					return fmt.Errorf("env var %s: %w", name, err)
+				}
+			}
+
+			v.Set(list)
+			return nil
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := applyEnvValue(fmt.Sprintf("%s_%d", name, i), v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasEnvPrefix(prefix string) bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer && t.Elem() == bigIntType {
+		return true
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func setScalar(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer && v.Type().Elem() == bigIntType {
+		n, ok := new(big.Int).SetString(raw, 0)
+		if !ok {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+
+		v.Set(reflect.ValueOf(n))
+		return nil
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(cfg *Config) bool
+		wantErr bool
+	}{
+		{"string", map[string]string{"TEST_SENDER_CHAINURL": "https://other.example.com"},
+			func(cfg *Config) bool { return cfg.Sender.ChainURL == "https://other.example.com" }, false},
+		{"uint", map[string]string{"TEST_SENDER_BUNDLELIFENUMBER": "42"},
+			func(cfg *Config) bool { return cfg.Sender.BundleLifeNumber == 42 }, false},
+		{"bool", map[string]string{"TEST_SENDER_SENDUNDERPRICED": "true"},
+			func(cfg *Config) bool { return cfg.Sender.SendUnderpriced }, false},
+		{"text unmarshaler", map[string]string{"TEST_SENDER_BLOCKINTERVAL": "450ms"},
+			func(cfg *Config) bool { return cfg.Sender.BlockInterval == txsender.Duration(450*time.Millisecond) }, false},
+		{"big int", map[string]string{"TEST_SENDER_POLICY_MAXVALUE": "0x10"},
+			func(cfg *Config) bool { return cfg.Sender.Policy.MaxValue.Cmp(big.NewInt(16)) == 0 }, false},
+		{"list entry in the file", map[string]string{"TEST_BUILDERS_0_KEY": "env-key"},
+			func(cfg *Config) bool { return cfg.Builders[0].Key == "env-key" }, false},
+		{"list entry not in the file", map[string]string{"TEST_BUILDERS_1_KEY": "env-key"},
+			func(cfg *Config) bool { return len(cfg.Builders) == 1 && cfg.Builders[0].Key == "file-key" }, false},
+		{"scalar list", map[string]string{"TEST_PROXY_TRUSTEDPROXIES": "10.0.0.1, 10.0.0.0/8"},
+			func(cfg *Config) bool {
+				return reflect.DeepEqual(cfg.Proxy.TrustedProxies, []string{"10.0.0.1", "10.0.0.0/8"})
+			}, false},
+		{"address list", map[string]string{"TEST_SENDER_POLICY_ALLOWEDSENDERS": "0x1000000000000000000000000000000000000001"},
+			func(cfg *Config) bool {
+				return reflect.DeepEqual(cfg.Sender.Policy.AllowedSenders, []common.Address{common.HexToAddress("0x1000000000000000000000000000000000000001")})
+			}, false},
+		{"section missing from the file", map[string]string{"TEST_BUILDERS_0_GENERIC_AUTH": "bearer"},
+			func(cfg *Config) bool {
+				return cfg.Builders[0].Generic != nil && cfg.Builders[0].Generic.Auth == builder.AuthBearer
+			}, false},
+		{"section in the file", map[string]string{"TEST_BUILDERS_0_BLOXROUTE_NETWORK": "BSC-Testnet"},
+			func(cfg *Config) bool {
+				return cfg.Builders[0].Bloxroute.Network == "BSC-Testnet" && reflect.DeepEqual(cfg.Builders[0].Bloxroute.MevBuilders, []string{"all"})
+			}, false},
+		{"section without env vars", nil, func(cfg *Config) bool { return cfg.Builders[0].Generic == nil }, false},
+		{"invalid uint", map[string]string{"TEST_SENDER_BUNDLELIFENUMBER": "many"}, nil, true},
+		{"invalid duration", map[string]string{"TEST_SENDER_BLOCKINTERVAL": "3"}, nil, true},
+		{"invalid big int", map[string]string{"TEST_SENDER_POLICY_MAXVALUE": "1e18"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg := &Config{
+				Sender: txsender.Config{ChainURL: "https://bsc-dataseed.bnbchain.org"},
+				Builders: []builder.Config{{
+					Brand:     builder.Nodereal,
+					URL:       "https://bsc-mainnet-builder-us.nodereal.io",
+					Key:       "file-key",
+					Bloxroute: &builder.BloxrouteConfig{MevBuilders: []string{"all"}},
+				}},
+			}
+
+			err := applyEnv("TEST", cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("applyEnv() = nil, want an error")
+				}
+				return
+			}
+
+			if err != nil || !tt.check(cfg) {
+				t.Fatalf("applyEnv() = %v, config %+v", err, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+// resolveRegions fills the urls of the builders naming a Region from the catalog of the chain,
+// urls set in the config take precedence over the catalog.
+func (c *Config) resolveRegions() error {
+	profile, ok := c.Chain.Profile()
+	if !ok {
+		// reported by Validate
+		return nil
+	}
+
+	if err := resolveBuilderRegions("Builders", profile.ChainID, c.Builders); err != nil {
+		return err
+	}
+
+	for idx, tenant := range c.Proxy.Tenants {
+		path := fmt.Sprintf("Proxy.Tenants[%d].Builders", idx)
+		if err := resolveBuilderRegions(path, profile.ChainID, tenant.Builders); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resolveBuilderRegions(path string, chainID uint64, builders []builder.Config) error {
+	for idx := range builders {
+		bc := &builders[idx]
+		if bc.Region == "" {
+			continue
+		}
+
+		if bc.URL != "" || len(bc.URLs) > 0 {
+			// the urls of the config are used as they are, they are not probed either
+			bc.Region = ""
+			continue
+		}
+
+		entries := builder.Catalog(chainID, bc.Brand)
+		if len(entries) == 0 {
+			return fmt.Errorf("%s[%d].Region: no known endpoint of %s on chain %d", path, idx, bc.Brand, chainID)
+		}
+
+		// auto probes the regions against each other, a single one leaves nothing to pick
+		if bc.Region == builder.RegionAuto && len(entries) < 2 {
+			return fmt.Errorf("%s[%d].Region: %s has a single known region on chain %d, set it or a url instead of %q",
+				path, idx, bc.Brand, chainID, builder.RegionAuto)
+		}
+
+		if bc.Region != builder.RegionAuto {
+			var regions []string
+			var matched []builder.CatalogEntry
+			for _, entry := range entries {
+				regions = append(regions, entry.Region)
+				if entry.Region == bc.Region {
+					matched = append(matched, entry)
+				}
+			}
+
+			if len(matched) == 0 {
+				return fmt.Errorf("%s[%d].Region: unknown region %q of %s, known: %s",
+					path, idx, bc.Region, bc.Brand, strings.Join(regions, ", "))
+			}
+
+			entries = matched
+		}
+
+		switch entries[0].Credential {
+		case builder.CredentialKey:
+			if bc.Key == "" {
+				return fmt.Errorf("%s[%d].Key: %s requires an api key", path, idx, bc.Brand)
+			}
+		case builder.CredentialSigner:
+			if bc.SignerKey == "" {
+				return fmt.Errorf("%s[%d].SignerKey: %s requires a signer key", path, idx, bc.Brand)
+			}
+		}
+
+		bc.URL = entries[0].URL
+		for _, entry := range entries[1:] {
+			bc.URLs = append(bc.URLs, entry.URL)
+		}
+	}
+
+	return nil
+}
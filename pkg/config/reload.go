@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+const watchInterval = 5 * time.Second
+
+// Watch reloads the config on SIGHUP or when the file is modified and hands it to reload,
+// an invalid config is logged and skipped. Rotated key files are only read again on SIGHUP.
+func Watch(ctx context.Context, path string, reload func(cfg *Config)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	modTime := fileModTime(path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Info("reload config on SIGHUP", "path", path)
+		case <-ticker.C:
+			latest := fileModTime(path)
+			if latest.Equal(modTime) {
+				continue
+			}
+
+			modTime = latest
+			log.Info("reload config on file change", "path", path)
+		}
+
+		cfg, err := Load(path)
+		if err != nil {
+			log.Error("failed to reload config, keep the current one", "path", path, "err", err)
+			continue
+		}
+
+		reload(cfg)
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// BuildersDiff lists the builder changes between two configs, builders are matched by their index
+// in the config since the same brand and url may be configured more than once.
+type BuildersDiff struct {
+	Added   []builder.Config
+	Removed []builder.Config
+	Updated []builder.Config // any field changed at the same index
+	Kept    []int            // indexes of the builders whose config did not change, their instances are reused
+}
+
+func (d BuildersDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+// Log logs the diff without the keys.
+func (d BuildersDiff) Log() {
+	for _, bc := range d.Added {
+		log.Info("builder added", "brand", bc.Brand, "url", bc.URL, "urls", bc.URLs)
+	}
+
+	for _, bc := range d.Removed {
+		log.Info("builder removed", "brand", bc.Brand, "url", bc.URL, "urls", bc.URLs)
+	}
+
+	for _, bc := range d.Updated {
+		log.Info("builder updated", "brand", bc.Brand, "url", bc.URL, "urls", bc.URLs)
+	}
+}
+
+func DiffBuilders(old, new []builder.Config) BuildersDiff {
+	diff := BuildersDiff{}
+	for i, bc := range new {
+		switch {
+		case i >= len(old):
+			diff.Added = append(diff.Added, bc)
+		case !reflect.DeepEqual(old[i], bc):
+			diff.Updated = append(diff.Updated, bc)
+		default:
+			diff.Kept = append(diff.Kept, i)
+		}
+	}
+
+	if len(old) > len(new) {
+		diff.Removed = append(diff.Removed, old[len(new):]...)
+	}
+
+	return diff
+}
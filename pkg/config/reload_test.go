@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+func TestDiffBuilders(t *testing.T) {
+	nodereal := builder.Config{Brand: builder.Nodereal, URL: "https://bsc-mainnet-builder.nodereal.io", Key: "key"}
+	flashbots := builder.Config{Brand: builder.Flashbots, URL: "https://bsc.flashbots.net", SignerKey: "signer"}
+
+	with := func(bc builder.Config, update func(*builder.Config)) builder.Config {
+		update(&bc)
+		return bc
+	}
+
+	tests := []struct {
+		name                    string
+		old, new                []builder.Config
+		added, removed, updated int
+	}{
+		{"unchanged", []builder.Config{nodereal, flashbots}, []builder.Config{nodereal, flashbots}, 0, 0, 0},
+		{"added", []builder.Config{nodereal}, []builder.Config{nodereal, flashbots}, 1, 0, 0},
+		{"removed", []builder.Config{nodereal, flashbots}, []builder.Config{nodereal}, 0, 1, 0},
+		{"key", []builder.Config{nodereal}, []builder.Config{with(nodereal, func(bc *builder.Config) { bc.Key = "new" })}, 0, 0, 1},
+		{"signer key", []builder.Config{flashbots},
+			[]builder.Config{with(flashbots, func(bc *builder.Config) { bc.SignerKey = "new" })}, 0, 0, 1},
+		{"urls", []builder.Config{nodereal},
+			[]builder.Config{with(nodereal, func(bc *builder.Config) { bc.URLs = []string{"https://a", "https://b"} })}, 0, 0, 1},
+		{"strategy", []builder.Config{nodereal},
+			[]builder.Config{with(nodereal, func(bc *builder.Config) { bc.Strategy = builder.StrategyRace })}, 0, 0, 1},
+		{"bloxroute options", []builder.Config{{Brand: builder.Bloxroute, URL: "https://api.blxrbdn.com"}},
+			[]builder.Config{{Brand: builder.Bloxroute, URL: "https://api.blxrbdn.com", Bloxroute: &builder.BloxrouteConfig{Network: "BSC-Testnet"}}}, 0, 0, 1},
+		{"duplicate added", []builder.Config{nodereal}, []builder.Config{nodereal, nodereal}, 1, 0, 0},
+		{"duplicate removed", []builder.Config{nodereal, nodereal}, []builder.Config{nodereal}, 0, 1, 0},
+		{"reordered", []builder.Config{nodereal, flashbots}, []builder.Config{flashbots, nodereal}, 0, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffBuilders(tt.old, tt.new)
+			if len(diff.Added) != tt.added || len(diff.Removed) != tt.removed || len(diff.Updated) != tt.updated {
+				t.Errorf("DiffBuilders() = %d added, %d removed, %d updated, want %d, %d, %d",
+					len(diff.Added), len(diff.Removed), len(diff.Updated), tt.added, tt.removed, tt.updated)
+			}
+
+			if len(diff.Kept)+tt.added+tt.updated != len(tt.new) {
+				t.Errorf("DiffBuilders() kept %v of %d builders", diff.Kept, len(tt.new))
+			}
+
+			if diff.Empty() != (tt.added+tt.removed+tt.updated == 0) {
+				t.Errorf("Empty() = %v", diff.Empty())
+			}
+		})
+	}
+}
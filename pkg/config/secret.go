@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+// A builder Key, SignerKey or SoulPointKey written as "file:<path>" is read from the file, and "env:<NAME>" from the env var,
+// so the secrets stay out of the config file.
+const (
+	KeyFilePrefix = "file:"
+	KeyEnvPrefix  = "env:"
+)
+
+func (c *Config) resolveKeys() error {
+	if err := resolveBuilderKeys("Builders", c.Builders); err != nil {
+		return err
+	}
+
+	for idx, tenant := range c.Proxy.Tenants {
+		if err := resolveBuilderKeys(fmt.Sprintf("Proxy.Tenants[%d].Builders", idx), tenant.Builders); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resolveBuilderKeys(path string, builders []builder.Config) error {
+	for idx := range builders {
+		key, err := resolveKey(builders[idx].Key)
+		if err != nil {
+			return fmt.Errorf("%s[%d].Key: %w", path, idx, err)
+		}
+
+		builders[idx].Key = key
+
+		signerKey, err := resolveKey(builders[idx].SignerKey)
+		if err != nil {
+			return fmt.Errorf("%s[%d].SignerKey: %w", path, idx, err)
+		}
+
+		builders[idx].SignerKey = signerKey
+
+		soulPointKey, err := resolveKey(builders[idx].SoulPointKey)
+		if err != nil {
+			return fmt.Errorf("%s[%d].SoulPointKey: %w", path, idx, err)
+		}
+
+		builders[idx].SoulPointKey = soulPointKey
+	}
+
+	return nil
+}
+
+func resolveKey(key string) (string, error) {
+	switch {
+	case strings.HasPrefix(key, KeyFilePrefix):
+		data, err := os.ReadFile(strings.TrimPrefix(key, KeyFilePrefix))
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	case strings.HasPrefix(key, KeyEnvPrefix):
+		name := strings.TrimPrefix(key, KeyEnvPrefix)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("env var %s is not set", name)
+		}
+
+		return value, nil
+	}
+
+	return key, nil
+}
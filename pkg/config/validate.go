@@ -0,0 +1,246 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/router"
+)
+
+// Validate reports every invalid field at once rather than stopping at the first.
+func (c *Config) Validate() error {
+	var err error
+
+	profile, ok := c.Chain.Profile()
+	if !ok {
+		err = multierror.Append(err, fmt.Errorf("Chain: unknown chain %q", c.Chain))
+	}
+
+	if ok && !profile.Parlia && len(c.Sender.Validators.Mapping) > 0 {
+		err = multierror.Append(err, fmt.Errorf("Sender.Validators: the validator mapping needs parlia, %s does not run it", c.Chain))
+	}
+
+	if e := validateURL(c.Sender.ChainURL); e != nil {
+		err = multierror.Append(err, fmt.Errorf("Sender.ChainURL: %w", e))
+	}
+
+	if c.Sender.BlockInterval <= 0 {
+		err = multierror.Append(err, errors.New("Sender.BlockInterval: must be positive"))
+	}
+
+	if len(c.Builders) == 0 {
+		err = multierror.Append(err, errors.New("Builders: no builder configured"))
+	}
+
+	err = validateBuilders(err, "Builders", c.Builders)
+
+	brands := c.brands()
+	for idx, m := range c.Sender.Validators.Mapping {
+		err = validateBrands(err, fmt.Sprintf("Sender.Validators.Mapping[%d].Builders", idx), m.Builders, brands)
+	}
+
+	for idx, rule := range c.Sender.Router.Rules {
+		path := fmt.Sprintf("Sender.Router.Rules[%d]", idx)
+		err = validateBrands(err, path+".Builders", rule.Builders, brands)
+		err = validateDispatch(err, path+".Dispatch", rule.Dispatch)
+	}
+
+	profiles := make(map[string]struct{}, len(c.Sender.Profiles))
+	for idx, profile := range c.Sender.Profiles {
+		path := fmt.Sprintf("Sender.Profiles[%d]", idx)
+		if profile.Name == "" {
+			err = multierror.Append(err, fmt.Errorf("%s.Name: must not be empty", path))
+		} else if _, ok := profiles[profile.Name]; ok {
+			err = multierror.Append(err, fmt.Errorf("%s.Name: duplicated profile %q", path, profile.Name))
+		}
+		profiles[profile.Name] = struct{}{}
+
+		if profile.Retries < 0 || profile.RetryInterval < 0 {
+			err = multierror.Append(err, fmt.Errorf("%s: Retries and RetryInterval must not be negative", path))
+		}
+
+		err = validateBrands(err, path+".Builders", profile.Builders, brands)
+		err = validateDispatch(err, path+".Dispatch", profile.Dispatch)
+	}
+
+	err = validateProfile(err, "Proxy.Profile", c.Proxy.Profile, profiles)
+
+	if c.Proxy.Guard.BanDuration < 0 {
+		err = multierror.Append(err, errors.New("Proxy.Guard.BanDuration: must not be negative"))
+	}
+
+	for idx, trusted := range c.Proxy.TrustedProxies {
+		if net.ParseIP(trusted) == nil {
+			if _, _, e := net.ParseCIDR(trusted); e != nil {
+				err = multierror.Append(err, fmt.Errorf("Proxy.TrustedProxies[%d]: invalid ip or cidr %q", idx, trusted))
+			}
+		}
+	}
+
+	apiKeys := make(map[string]struct{})
+	for idx, tenant := range c.Proxy.Tenants {
+		path := fmt.Sprintf("Proxy.Tenants[%d]", idx)
+		if len(tenant.APIKeys) == 0 {
+			err = multierror.Append(err, fmt.Errorf("%s.APIKeys: no api key configured", path))
+		}
+
+		for _, key := range tenant.APIKeys {
+			if _, ok := apiKeys[key]; ok {
+				err = multierror.Append(err, fmt.Errorf("%s.APIKeys: key shared with another tenant", path))
+			}
+			apiKeys[key] = struct{}{}
+		}
+
+		err = validateBuilders(err, path+".Builders", tenant.Builders)
+		err = validateProfile(err, path+".Profile", tenant.Profile, profiles)
+	}
+
+	return err
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid url %q", raw)
+	}
+
+	return nil
+}
+
+func validateBuilders(err error, path string, builders []builder.Config) error {
+	for idx, bc := range builders {
+		if !bc.Brand.Valid() {
+			err = multierror.Append(err, fmt.Errorf("%s[%d].Brand: unknown brand %q", path, idx, bc.Brand))
+		}
+
+		if bc.URL == "" && len(bc.URLs) == 0 {
+			err = multierror.Append(err, fmt.Errorf("%s[%d].URL: URL, URLs or Region is required", path, idx))
+		}
+
+		if bc.URL != "" {
+			if e := validateURL(bc.URL); e != nil {
+				err = multierror.Append(err, fmt.Errorf("%s[%d].URL: %w", path, idx, e))
+			}
+		}
+
+		for i, u := range bc.URLs {
+			if e := validateURL(u); e != nil {
+				err = multierror.Append(err, fmt.Errorf("%s[%d].URLs[%d]: %w", path, idx, i, e))
+			}
+		}
+
+		if !bc.Strategy.Valid() {
+			err = multierror.Append(err, fmt.Errorf("%s[%d].Strategy: unknown strategy %q", path, idx, bc.Strategy))
+		}
+
+		if bc.HedgeDelay < 0 {
+			err = multierror.Append(err, fmt.Errorf("%s[%d].HedgeDelay: must not be negative", path, idx))
+		}
+
+		if bc.KeepAlive < 0 {
+			err = multierror.Append(err, fmt.Errorf("%s[%d].KeepAlive: must not be negative", path, idx))
+		}
+
+		err = validateWebsocket(err, fmt.Sprintf("%s[%d].URL", path, idx), bc, bc.URL)
+		for i, u := range bc.URLs {
+			err = validateWebsocket(err, fmt.Sprintf("%s[%d].URLs[%d]", path, idx, i), bc, u)
+		}
+
+		// flashbots authenticates the bundles by the signature of the signer key only
+		if bc.Brand == builder.Flashbots && bc.SignerKey == "" {
+			err = multierror.Append(err, fmt.Errorf("%s[%d].SignerKey: %s requires a signer key", path, idx, bc.Brand))
+		}
+
+		if bc.Brand == builder.Generic && bc.Generic != nil {
+			if e := bc.Generic.Validate(); e != nil {
+				err = multierror.Append(err, fmt.Errorf("%s[%d].Generic: %w", path, idx, e))
+			}
+		}
+	}
+
+	return err
+}
+
+// validateWebsocket rejects a ws:// or wss:// url the builder would send over http, only the single
+// url of a bloxroute builder is dialed as a websocket. The signature is an http header, a websocket
+// message has none.
+func validateWebsocket(err error, path string, bc builder.Config, raw string) error {
+	if !strings.HasPrefix(raw, "ws://") && !strings.HasPrefix(raw, "wss://") {
+		return err
+	}
+
+	if bc.Brand != builder.Bloxroute || len(bc.Endpoints()) != 1 {
+		err = multierror.Append(err, fmt.Errorf("%s: websocket urls are only supported as the single url of a %s builder", path, builder.Bloxroute))
+	}
+
+	if bc.SignerKey != "" {
+		err = multierror.Append(err, fmt.Errorf("%s: signed requests need an http url", path))
+	}
+
+	return err
+}
+
+// brands returns the brands the builders are reported under, the registered ones and the
+// names given to the generic builders.
+func (c *Config) brands() map[builder.Brand]struct{} {
+	brands := make(map[builder.Brand]struct{})
+	for _, brand := range builder.Brands() {
+		brands[brand] = struct{}{}
+	}
+
+	addGeneric := func(builders []builder.Config) {
+		for _, bc := range builders {
+			if bc.Brand == builder.Generic && bc.Generic != nil && bc.Generic.Name != "" {
+				brands[builder.Brand(bc.Generic.Name)] = struct{}{}
+			}
+		}
+	}
+
+	addGeneric(c.Builders)
+	for _, tenant := range c.Proxy.Tenants {
+		addGeneric(tenant.Builders)
+	}
+
+	return brands
+}
+
+func validateBrands(err error, path string, brands []builder.Brand, known map[builder.Brand]struct{}) error {
+	for _, brand := range brands {
+		if _, ok := known[brand]; !ok {
+			err = multierror.Append(err, fmt.Errorf("%s: unknown brand %q", path, brand))
+		}
+	}
+
+	return err
+}
+
+func validateDispatch(err error, path string, dispatch router.Dispatch) error {
+	switch dispatch {
+	case "", router.DispatchFirst, router.DispatchAll:
+		return err
+	}
+
+	return multierror.Append(err, fmt.Errorf("%s: unknown dispatch %q", path, dispatch))
+}
+
+func validateProfile(err error, path, name string, profiles map[string]struct{}) error {
+	if name == "" {
+		return err
+	}
+
+	if _, ok := profiles[name]; !ok {
+		return multierror.Append(err, fmt.Errorf("%s: unknown profile %q", path, name))
+	}
+
+	return err
+}
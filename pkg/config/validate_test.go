@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/proxy"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		cfg := &Config{
+			Sender:   txsender.Config{ChainURL: "https://bsc-dataseed.bnbchain.org"},
+			Builders: []builder.Config{{Brand: builder.Nodereal, URL: "https://bsc-mainnet-builder.nodereal.io", Key: "key"}},
+		}
+		cfg.applyDefaults()
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		update  func(cfg *Config)
+		wantErr string
+	}{
+		{"valid", func(cfg *Config) {}, ""},
+		{"unknown chain", func(cfg *Config) { cfg.Chain = "solana" }, "Chain: unknown chain"},
+		{"invalid chain url", func(cfg *Config) { cfg.Sender.ChainURL = "localhost" }, "Sender.ChainURL"},
+		{"no builder", func(cfg *Config) { cfg.Builders = nil }, "Builders: no builder configured"},
+		{"unknown brand", func(cfg *Config) { cfg.Builders[0].Brand = "unknown" }, "Builders[0].Brand"},
+		{"urls only", func(cfg *Config) {
+			cfg.Builders[0].URL, cfg.Builders[0].URLs = "", []string{"https://a.example.com", "https://b.example.com"}
+		}, ""},
+		{"no url", func(cfg *Config) { cfg.Builders[0].URL = "" }, "Builders[0].URL: URL, URLs or Region is required"},
+		{"invalid urls entry", func(cfg *Config) { cfg.Builders[0].URLs = []string{"a.example.com"} }, "Builders[0].URLs[0]"},
+		{"bloxroute websocket", func(cfg *Config) {
+			cfg.Builders[0] = builder.Config{Brand: builder.Bloxroute, URL: "wss://api.blxrbdn.com/ws", Key: "key"}
+		}, ""},
+		{"websocket in urls", func(cfg *Config) {
+			cfg.Builders[0] = builder.Config{Brand: builder.Bloxroute, URLs: []string{"https://a.example.com", "wss://b.example.com"}}
+		}, "Builders[0].URLs[1]: websocket urls"},
+		{"websocket of another brand", func(cfg *Config) { cfg.Builders[0].URL = "wss://a.example.com" }, "Builders[0].URL: websocket urls"},
+		{"signed websocket", func(cfg *Config) {
+			cfg.Builders[0] = builder.Config{Brand: builder.Bloxroute, URLs: []string{"wss://b.example.com"}, SignerKey: "key"}
+		}, "Builders[0].URLs[0]: signed requests"},
+		{"unknown strategy", func(cfg *Config) { cfg.Builders[0].Strategy = "fastest" }, "Builders[0].Strategy"},
+		{"negative hedge delay", func(cfg *Config) { cfg.Builders[0].HedgeDelay = -1 }, "Builders[0].HedgeDelay"},
+		{"flashbots without signer key", func(cfg *Config) {
+			cfg.Builders[0] = builder.Config{Brand: builder.Flashbots, URL: "https://relay.flashbots.net"}
+		}, "Builders[0].SignerKey"},
+		{"duplicated profile", func(cfg *Config) {
+			cfg.Sender.Profiles = []txsender.Profile{{Name: "fast"}, {Name: "fast"}}
+		}, "duplicated profile"},
+		{"unknown proxy profile", func(cfg *Config) { cfg.Proxy.Profile = "fast" }, "Proxy.Profile"},
+		{"invalid trusted proxy", func(cfg *Config) { cfg.Proxy.TrustedProxies = []string{"10.0.0.0/33"} }, "Proxy.TrustedProxies[0]"},
+		{"tenant without api key", func(cfg *Config) { cfg.Proxy.Tenants = []proxy.TenantConfig{{Name: "acme"}} }, "Proxy.Tenants[0].APIKeys"},
+		{"shared api key", func(cfg *Config) {
+			cfg.Proxy.Tenants = []proxy.TenantConfig{{Name: "a", APIKeys: []string{"k"}}, {Name: "b", APIKeys: []string{"k"}}}
+		}, "key shared with another tenant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.update(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package policy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "paymaster"
+
+// subsystem
+const (
+	system = "policy"
+)
+
+var (
+	ViolationCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "violation",
+	}, []string{"rule"})
+)
@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Selector is the 4-byte function selector at the start of the calldata.
+type Selector [4]byte
+
+func (s Selector) String() string {
+	return hexutil.Encode(s[:])
+}
+
+func (s Selector) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Selector) UnmarshalText(text []byte) error {
+	b, err := hexutil.Decode(string(text))
+	if err != nil {
+		return err
+	}
+
+	if len(b) != len(s) {
+		return fmt.Errorf("invalid selector %q, want 4 bytes", text)
+	}
+
+	copy(s[:], b)
+	return nil
+}
+
+// Config lists the guardrails of outbound private txs, an empty list or a nil limit disables the rule.
+// Allow lists are checked before deny lists.
+type Config struct {
+	AllowedSenders   []common.Address
+	AllowedTo        []common.Address
+	DeniedTo         []common.Address
+	AllowedSelectors []Selector
+	DeniedSelectors  []Selector
+	MaxValue         *big.Int // in wei
+	MaxGasPrice      *big.Int // in wei, compared with the gas fee cap
+	MaxGas           uint64
+}
+
+const (
+	RuleSender      = "sender"
+	RuleTo          = "to"
+	RuleSelector    = "selector"
+	RuleMaxValue    = "max_value"
+	RuleMaxGasPrice = "max_gas_price"
+	RuleMaxGas      = "max_gas"
+)
+
+// Violation is returned when a tx breaks a rule of the policy.
+type Violation struct {
+	Rule    string      `json:"rule"`
+	TxHash  common.Hash `json:"txHash"`
+	Message string      `json:"message"`
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("policy violation, rule: %s, tx: %s, %s", v.Rule, v.TxHash, v.Message)
+}
+
+type Policy struct {
+	cfg              Config
+	allowedSenders   map[common.Address]struct{}
+	allowedTo        map[common.Address]struct{}
+	deniedTo         map[common.Address]struct{}
+	allowedSelectors map[Selector]struct{}
+	deniedSelectors  map[Selector]struct{}
+}
+
+func New(cfg Config) *Policy {
+	return &Policy{
+		cfg:              cfg,
+		allowedSenders:   toSet(cfg.AllowedSenders),
+		allowedTo:        toSet(cfg.AllowedTo),
+		deniedTo:         toSet(cfg.DeniedTo),
+		allowedSelectors: toSet(cfg.AllowedSelectors),
+		deniedSelectors:  toSet(cfg.DeniedSelectors),
+	}
+}
+
+func toSet[T comparable](items []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+
+	return set
+}
+
+// Evaluate checks the tx sent by from against every rule and returns the first *Violation.
+func (p *Policy) Evaluate(tx *types.Transaction, from common.Address) error {
+	if len(p.allowedSenders) > 0 {
+		if _, ok := p.allowedSenders[from]; !ok {
+			return violation(tx, RuleSender, "sender %s is not allowed", from)
+		}
+	}
+
+	if err := p.evaluateTo(tx); err != nil {
+		return err
+	}
+
+	if err := p.evaluateSelector(tx); err != nil {
+		return err
+	}
+
+	if p.cfg.MaxValue != nil && tx.Value().Cmp(p.cfg.MaxValue) > 0 {
+		return violation(tx, RuleMaxValue, "value %s exceeds %s", tx.Value(), p.cfg.MaxValue)
+	}
+
+	if p.cfg.MaxGasPrice != nil && tx.GasFeeCap().Cmp(p.cfg.MaxGasPrice) > 0 {
+		return violation(tx, RuleMaxGasPrice, "gas price %s exceeds %s", tx.GasFeeCap(), p.cfg.MaxGasPrice)
+	}
+
+	if p.cfg.MaxGas > 0 && tx.Gas() > p.cfg.MaxGas {
+		return violation(tx, RuleMaxGas, "gas limit %d exceeds %d", tx.Gas(), p.cfg.MaxGas)
+	}
+
+	return nil
+}
+
+func violation(tx *types.Transaction, rule, format string, args ...interface{}) error {
+	ViolationCounter.WithLabelValues(rule).Inc()
+	return &Violation{Rule: rule, TxHash: tx.Hash(), Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *Policy) evaluateTo(tx *types.Transaction) error {
+	to := tx.To()
+	if to == nil {
+		if len(p.allowedTo) > 0 {
+			return violation(tx, RuleTo, "contract creation is not allowed")
+		}
+
+		return nil
+	}
+
+	if len(p.allowedTo) > 0 {
+		if _, ok := p.allowedTo[*to]; !ok {
+			return violation(tx, RuleTo, "to %s is not allowed", to)
+		}
+	}
+
+	if _, ok := p.deniedTo[*to]; ok {
+		return violation(tx, RuleTo, "to %s is denied", to)
+	}
+
+	return nil
+}
+
+func (p *Policy) evaluateSelector(tx *types.Transaction) error {
+	if len(p.allowedSelectors) == 0 && len(p.deniedSelectors) == 0 {
+		return nil
+	}
+
+	// calls without a selector, such as plain transfers, never match the allow list
+	if len(tx.Data()) < len(Selector{}) {
+		if len(p.allowedSelectors) > 0 {
+			return violation(tx, RuleSelector, "call without selector is not allowed")
+		}
+
+		return nil
+	}
+
+	var selector Selector
+	copy(selector[:], tx.Data())
+
+	if len(p.allowedSelectors) > 0 {
+		if _, ok := p.allowedSelectors[selector]; !ok {
+			return violation(tx, RuleSelector, "selector %s is not allowed", selector)
+		}
+	}
+
+	if _, ok := p.deniedSelectors[selector]; ok {
+		return violation(tx, RuleSelector, "selector %s is denied", selector)
+	}
+
+	return nil
+}
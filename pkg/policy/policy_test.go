@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestEvaluate(t *testing.T) {
+	var (
+		sender   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		other    = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		router   = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		drainer  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		swap     = Selector{0x38, 0xed, 0x17, 0x39}
+		approve  = Selector{0x09, 0x5e, 0xa7, 0xb3}
+		transfer = Selector{0xa9, 0x05, 0x9c, 0xbb}
+	)
+
+	newTx := func(to *common.Address, value int64, gasPrice int64, gas uint64, data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: to, Value: big.NewInt(value), GasPrice: big.NewInt(gasPrice), Gas: gas, Data: data})
+	}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		tx       *types.Transaction
+		from     common.Address
+		wantRule string
+	}{
+		{"empty policy", Config{}, newTx(&router, 1, 1, 21000, nil), sender, ""},
+		{"allowed sender", Config{AllowedSenders: []common.Address{sender}}, newTx(&router, 1, 1, 21000, nil), sender, ""},
+		{"sender not allowed", Config{AllowedSenders: []common.Address{sender}}, newTx(&router, 1, 1, 21000, nil), other, RuleSender},
+		{"to not allowed", Config{AllowedTo: []common.Address{router}}, newTx(&drainer, 1, 1, 21000, nil), sender, RuleTo},
+		{"contract creation with allowed to", Config{AllowedTo: []common.Address{router}}, newTx(nil, 0, 1, 21000, nil), sender, RuleTo},
+		{"contract creation with denied to", Config{DeniedTo: []common.Address{drainer}}, newTx(nil, 0, 1, 21000, nil), sender, ""},
+		{"to denied", Config{DeniedTo: []common.Address{drainer}}, newTx(&drainer, 1, 1, 21000, nil), sender, RuleTo},
+		{"allowed selector", Config{AllowedSelectors: []Selector{swap}}, newTx(&router, 0, 1, 21000, append(swap[:], 1, 2)), sender, ""},
+		{"selector not allowed", Config{AllowedSelectors: []Selector{swap}}, newTx(&router, 0, 1, 21000, approve[:]), sender, RuleSelector},
+		{"transfer with allowed selectors", Config{AllowedSelectors: []Selector{swap}}, newTx(&router, 1, 1, 21000, nil), sender, RuleSelector},
+		{"transfer with denied selectors", Config{DeniedSelectors: []Selector{approve}}, newTx(&router, 1, 1, 21000, nil), sender, ""},
+		{"selector denied", Config{DeniedSelectors: []Selector{approve, transfer}}, newTx(&router, 0, 1, 21000, approve[:]), sender, RuleSelector},
+		{"allow list checked first", Config{AllowedTo: []common.Address{router}, DeniedTo: []common.Address{router}},
+			newTx(&router, 1, 1, 21000, nil), sender, RuleTo},
+		{"max value", Config{MaxValue: big.NewInt(10)}, newTx(&router, 10, 1, 21000, nil), sender, ""},
+		{"value exceeds", Config{MaxValue: big.NewInt(10)}, newTx(&router, 11, 1, 21000, nil), sender, RuleMaxValue},
+		{"gas price exceeds", Config{MaxGasPrice: big.NewInt(5)}, newTx(&router, 1, 6, 21000, nil), sender, RuleMaxGasPrice},
+		{"gas exceeds", Config{MaxGas: 21000}, newTx(&router, 1, 1, 21001, nil), sender, RuleMaxGas},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.cfg).Evaluate(tt.tx, tt.from)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("Evaluate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var v *Violation
+			if !errors.As(err, &v) || v.Rule != tt.wantRule {
+				t.Fatalf("Evaluate() = %v, want a violation of rule %s", err, tt.wantRule)
+			}
+
+			if v.TxHash != tt.tx.Hash() {
+				t.Errorf("Violation.TxHash = %s, want %s", v.TxHash, tt.tx.Hash())
+			}
+		})
+	}
+}
+
+func TestSelectorUnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Selector
+		wantErr bool
+	}{
+		{"0x38ed1739", Selector{0x38, 0xed, 0x17, 0x39}, false},
+		{"0x38ed17", Selector{}, true},
+		{"38ed1739", Selector{}, true},
+		{"0x38ed1739ff", Selector{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got Selector
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("UnmarshalText() = %v, %v, want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
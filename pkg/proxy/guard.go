@@ -0,0 +1,219 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+// GuardConfig protects the builders from junk txs, every check is disabled when left zero.
+type GuardConfig struct {
+	TxPerIP         uint64 // txs per minute from a client ip
+	TxPerSender     uint64 // txs per minute from a sender address
+	MinGasPrice     uint64 // in wei, compared with the gas tip cap which is what a tx pays on BSC
+	MaxCalldataSize int
+	// MaxSimulationFailures bans a sender once that many of its txs failed the builders' simulation
+	MaxSimulationFailures int
+	BanDuration           txsender.Duration
+}
+
+const defaultBanDuration = time.Hour
+
+// windowCounter counts events per key in fixed one minute windows.
+type windowCounter struct {
+	mu     sync.Mutex
+	window int64
+	counts map[string]uint64
+}
+
+func newWindowCounter() *windowCounter {
+	return &windowCounter{counts: make(map[string]uint64)}
+}
+
+// take counts an event of the key, it returns false without counting when the limit is reached.
+func (w *windowCounter) take(key string, limit uint64, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if window := now.Unix() / 60; window != w.window {
+		w.window, w.counts = window, make(map[string]uint64)
+	}
+
+	if w.counts[key] >= limit {
+		return false
+	}
+
+	w.counts[key]++
+	return true
+}
+
+type guard struct {
+	cfg         GuardConfig
+	banDuration time.Duration
+	ipCounter   *windowCounter
+	fromCounter *windowCounter
+
+	mu       sync.Mutex
+	failures map[common.Address]failures
+	failed   map[common.Hash]time.Time
+	banned   map[common.Address]time.Time // ban expiry
+}
+
+// failures counts the simulation failures of a sender, they are forgotten a ban duration after the last one.
+type failures struct {
+	count int
+	last  time.Time
+}
+
+func newGuard(cfg GuardConfig) *guard {
+	g := &guard{
+		cfg:         cfg,
+		banDuration: time.Duration(cfg.BanDuration),
+		ipCounter:   newWindowCounter(),
+		fromCounter: newWindowCounter(),
+		failures:    make(map[common.Address]failures),
+		failed:      make(map[common.Hash]time.Time),
+		banned:      make(map[common.Address]time.Time),
+	}
+
+	if g.banDuration == 0 {
+		g.banDuration = defaultBanDuration
+	}
+
+	return g
+}
+
+func rejected(reason, format string, args ...interface{}) error {
+	RejectedCounter.WithLabelValues(reason).Inc()
+	return &rpc.JsonrpcError{Code: rpc.TransactionRejectedCode, Message: fmt.Sprintf(format, args...)}
+}
+
+// check runs before the tx is handed to the sender.
+func (g *guard) check(ip string, tx *types.Transaction, from common.Address) error {
+	now := time.Now()
+
+	if g.isBanned(from, now) {
+		return rejected("banned", "sender %s is temporarily banned", from)
+	}
+
+	if g.hasFailed(tx.Hash()) {
+		return rejected("failed_before", "tx %s already failed simulation", tx.Hash())
+	}
+
+	if g.cfg.MaxCalldataSize > 0 && len(tx.Data()) > g.cfg.MaxCalldataSize {
+		return rejected("calldata_size", "calldata size %d exceeds %d", len(tx.Data()), g.cfg.MaxCalldataSize)
+	}
+
+	if g.cfg.MinGasPrice > 0 && tx.GasTipCap().Cmp(new(big.Int).SetUint64(g.cfg.MinGasPrice)) < 0 {
+		return rejected("gas_price", "gas price %s below minimum %d", tx.GasTipCap(), g.cfg.MinGasPrice)
+	}
+
+	if err := g.checkIP(ip, now); err != nil {
+		return err
+	}
+
+	if g.cfg.TxPerSender > 0 && !g.fromCounter.take(from.Hex(), g.cfg.TxPerSender, now) {
+		return rejected("sender_rate", "rate limit of %d txs per minute exceeded for %s", g.cfg.TxPerSender, from)
+	}
+
+	return nil
+}
+
+// checkIP counts a request of the client ip, it guards the methods reaching the builders without a tx.
+func (g *guard) checkIP(ip string, now time.Time) error {
+	if g.cfg.TxPerIP > 0 && !g.ipCounter.take(ip, g.cfg.TxPerIP, now) {
+		return rejected("ip_rate", "rate limit of %d txs per minute exceeded for %s", g.cfg.TxPerIP, ip)
+	}
+
+	return nil
+}
+
+func (g *guard) isBanned(from common.Address, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	expiry, ok := g.banned[from]
+	if ok && now.After(expiry) {
+		delete(g.banned, from)
+		return false
+	}
+
+	return ok
+}
+
+func (g *guard) hasFailed(txHash common.Hash) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.failed[txHash]
+	return ok
+}
+
+// recordFailure remembers a tx which failed simulation for the ban duration and bans its sender after too many failures.
+func (g *guard) recordFailure(txHash common.Hash, from common.Address) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	g.sweep(now)
+
+	g.failed[txHash] = now
+
+	if g.cfg.MaxSimulationFailures <= 0 {
+		return
+	}
+
+	f := g.failures[from]
+	f.count++
+	f.last = now
+	g.failures[from] = f
+
+	if f.count >= g.cfg.MaxSimulationFailures {
+		log.Warn("ban sender failing simulation", "from", from, "failures", f.count, "duration", g.banDuration)
+
+		g.banned[from] = now.Add(g.banDuration)
+		delete(g.failures, from)
+	}
+}
+
+// sweep drops the failed txs and failure counts older than the ban duration and the expired bans,
+// so the guard does not grow with every sender ever seen.
+func (g *guard) sweep(now time.Time) {
+	for hash, failedAt := range g.failed {
+		if now.Sub(failedAt) > g.banDuration {
+			delete(g.failed, hash)
+		}
+	}
+
+	for from, f := range g.failures {
+		if now.Sub(f.last) > g.banDuration {
+			delete(g.failures, from)
+		}
+	}
+
+	for from, expiry := range g.banned {
+		if now.After(expiry) {
+			delete(g.banned, from)
+		}
+	}
+}
+
+type clientIPKey struct{}
+
+func withClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func clientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
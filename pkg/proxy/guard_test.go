@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func TestGuardCheck(t *testing.T) {
+	from := common.HexToAddress("0x01")
+	tx := func(nonce uint64, tip int64, data []byte) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{Nonce: nonce, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(tip), Data: data})
+	}
+
+	tests := []struct {
+		name    string
+		cfg     GuardConfig
+		txs     []*types.Transaction
+		ips     []string
+		wantErr []bool
+	}{
+		{"no checks", GuardConfig{}, []*types.Transaction{tx(0, 0, nil)}, []string{"1.1.1.1"}, []bool{false}},
+		{"gas price", GuardConfig{MinGasPrice: 10}, []*types.Transaction{tx(0, 9, nil), tx(1, 10, nil)}, []string{"a", "a"}, []bool{true, false}},
+		{"calldata size", GuardConfig{MaxCalldataSize: 2}, []*types.Transaction{tx(0, 0, []byte{1, 2, 3})}, []string{"a"}, []bool{true}},
+		{"ip rate", GuardConfig{TxPerIP: 1}, []*types.Transaction{tx(0, 0, nil), tx(1, 0, nil), tx(2, 0, nil)}, []string{"a", "a", "b"},
+			[]bool{false, true, false}},
+		{"sender rate", GuardConfig{TxPerSender: 1}, []*types.Transaction{tx(0, 0, nil), tx(1, 0, nil)}, []string{"a", "b"}, []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGuard(tt.cfg)
+			for idx, tx := range tt.txs {
+				err := g.check(tt.ips[idx], tx, from)
+				if (err != nil) != tt.wantErr[idx] {
+					t.Errorf("tx %d: check() = %v, want error %v", idx, err, tt.wantErr[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestGuardSimulationFailures(t *testing.T) {
+	from := common.HexToAddress("0x01")
+	g := newGuard(GuardConfig{MaxSimulationFailures: 2, BanDuration: txsender.Duration(time.Hour)})
+
+	g.recordFailure(common.HexToHash("0x01"), from)
+	if g.isBanned(from, time.Now()) {
+		t.Fatal("banned after a single failure")
+	}
+
+	if !g.hasFailed(common.HexToHash("0x01")) {
+		t.Fatal("failed tx not remembered")
+	}
+
+	g.recordFailure(common.HexToHash("0x02"), from)
+	if !g.isBanned(from, time.Now()) {
+		t.Fatal("not banned after MaxSimulationFailures")
+	}
+
+	if g.isBanned(from, time.Now().Add(2*time.Hour)) {
+		t.Fatal("still banned after the ban duration")
+	}
+}
+
+func TestGuardSweep(t *testing.T) {
+	g := newGuard(GuardConfig{MaxSimulationFailures: 3, BanDuration: txsender.Duration(time.Minute)})
+
+	old := time.Now().Add(-2 * time.Minute)
+	g.failed[common.HexToHash("0x01")] = old
+	g.failures[common.HexToAddress("0x01")] = failures{count: 1, last: old}
+	g.banned[common.HexToAddress("0x02")] = old
+
+	g.recordFailure(common.HexToHash("0x03"), common.HexToAddress("0x03"))
+
+	if len(g.failed) != 1 || len(g.failures) != 1 || len(g.banned) != 0 {
+		t.Errorf("sweep kept %d failed txs, %d failure counts and %d bans, want 1, 1 and 0",
+			len(g.failed), len(g.failures), len(g.banned))
+	}
+}
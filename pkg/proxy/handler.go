@@ -0,0 +1,239 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func invalidParams(format string, args ...interface{}) error {
+	return &rpc.JsonrpcError{Code: rpc.InvalidParamsCode, Message: fmt.Sprintf(format, args...)}
+}
+
+// decodeParams unmarshals the positional params into args, missing trailing params are left untouched.
+func decodeParams(req *rpc.Request, args ...interface{}) error {
+	if len(req.Params) > len(args) {
+		return invalidParams("too many params, want at most %d", len(args))
+	}
+
+	for idx, param := range req.Params {
+		if err := jsoniter.Unmarshal(param, args[idx]); err != nil {
+			return invalidParams("invalid param %d: %v", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// admit decodes the tx and runs the spam checks on it before it reaches the sender.
+func (s *Server) admit(ctx context.Context, input hexutil.Bytes) (*pendingTx, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, invalidParams("invalid transaction: %v", err)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, invalidParams("invalid sender: %v", err)
+	}
+
+	if err := s.guard.check(clientIPFromContext(ctx), tx, from); err != nil {
+		return nil, err
+	}
+
+	return &pendingTx{tx: tx, from: from}, nil
+}
+
+// admitBundle admits every tx of the bundle.
+func (s *Server) admitBundle(ctx context.Context, args *types.SendBundleArgs) ([]*pendingTx, error) {
+	txs := make([]*pendingTx, 0, len(args.Txs))
+	for _, input := range args.Txs {
+		tx, err := s.admit(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// recordSimulationFailure hands the tx the builders' simulation rejected to the guard.
+func (s *Server) recordSimulationFailure(err error, txs ...*pendingTx) {
+	simErr := &txsender.SimulationError{}
+	if !errors.As(err, &simErr) {
+		return
+	}
+
+	for _, tx := range txs {
+		if tx.tx.Hash() == simErr.TxHash {
+			s.guard.recordFailure(simErr.TxHash, tx.from)
+		}
+	}
+}
+
+func (s *Server) sendRawTransaction(ctx context.Context, req *rpc.Request) (interface{}, error) {
+	var input hexutil.Bytes
+	if err := decodeParams(req, &input); err != nil {
+		return nil, err
+	}
+
+	if len(input) == 0 {
+		return nil, invalidParams("missing raw transaction")
+	}
+
+	tx, err := s.admit(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := takeQuota(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	result, err := s.sender.SendRawTransaction(ctx, input, s.cfg.AllowRevert, s.sendOptions(ctx)...)
+	if err != nil {
+		s.recordSimulationFailure(err, tx)
+		return nil, err
+	}
+
+	s.trackPending(input)
+	return result.TxHash, nil
+}
+
+// privateTransactionArgs is the param of eth_sendPrivateTransaction, the bundle window of the
+// sender is always used so maxBlockNumber and preferences are accepted but ignored.
+type privateTransactionArgs struct {
+	Tx             hexutil.Bytes          `json:"tx"`
+	MaxBlockNumber *hexutil.Uint64        `json:"maxBlockNumber,omitempty"`
+	Preferences    map[string]interface{} `json:"preferences,omitempty"`
+}
+
+func (s *Server) sendPrivateTransaction(ctx context.Context, req *rpc.Request) (interface{}, error) {
+	args := privateTransactionArgs{}
+	if err := decodeParams(req, &args); err != nil {
+		return nil, err
+	}
+
+	if len(args.Tx) == 0 {
+		return nil, invalidParams("missing tx")
+	}
+
+	tx, err := s.admit(ctx, args.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := takeQuota(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	result, err := s.sender.SendRawTransaction(ctx, args.Tx, s.cfg.AllowRevert, s.sendOptions(ctx)...)
+	if err != nil {
+		s.recordSimulationFailure(err, tx)
+		return nil, err
+	}
+
+	s.trackPending(args.Tx)
+	return result.TxHash, nil
+}
+
+// bundleResults is the aggregated result of the bundle methods, one entry per builder.
+type bundleResults struct {
+	Bundles []txsender.BuilderResult `json:"bundles"`
+}
+
+func decodeBundleArgs(req *rpc.Request) (*types.SendBundleArgs, error) {
+	args := &types.SendBundleArgs{}
+	if err := decodeParams(req, args); err != nil {
+		return nil, err
+	}
+
+	if len(args.Txs) == 0 {
+		return nil, invalidParams("empty bundle")
+	}
+
+	return args, nil
+}
+
+func (s *Server) sendBundle(ctx context.Context, req *rpc.Request) (interface{}, error) {
+	args, err := decodeBundleArgs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := s.admitBundle(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := takeQuota(ctx, uint64(len(args.Txs))); err != nil {
+		return nil, err
+	}
+
+	results, err := s.sender.SendBundle(ctx, args, s.sendOptions(ctx)...)
+	if err != nil {
+		s.recordSimulationFailure(err, txs...)
+		return nil, err
+	}
+
+	return &bundleResults{Bundles: results}, nil
+}
+
+func (s *Server) callBundle(ctx context.Context, req *rpc.Request) (interface{}, error) {
+	args, err := decodeBundleArgs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.admitBundle(ctx, args); err != nil {
+		return nil, err
+	}
+
+	results, err := s.sender.CallBundle(ctx, args, s.sendOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundleResults{Bundles: results}, nil
+}
+
+// cancelBundle takes the result of eth_sendBundle, so each builder cancels the bundle id it returned.
+func (s *Server) cancelBundle(ctx context.Context, req *rpc.Request) (interface{}, error) {
+	args := bundleResults{}
+	if err := decodeParams(req, &args); err != nil {
+		return nil, err
+	}
+
+	if err := s.guard.checkIP(clientIPFromContext(ctx), time.Now()); err != nil {
+		return nil, err
+	}
+
+	bundleIDs := make(map[string]builder.BundleID, len(args.Bundles))
+	for _, b := range args.Bundles {
+		if b.BundleID != "" {
+			bundleIDs[b.ID] = b.BundleID
+		}
+	}
+
+	if len(bundleIDs) == 0 {
+		return nil, invalidParams("no bundle ids")
+	}
+
+	results, err := s.sender.CancelBundle(ctx, bundleIDs, s.sendOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundleResults{Bundles: results}, nil
+}
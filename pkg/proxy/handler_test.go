@@ -0,0 +1,168 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+// stubSender answers the sends with err and the status of the txs from statuses, the methods
+// it does not override panic.
+type stubSender struct {
+	txsender.PrivateTxSender
+	err      error
+	calls    int
+	statuses map[common.Hash]txsender.TxStatus
+}
+
+func (s *stubSender) TxStatus(txHash common.Hash) (txsender.TxStatus, bool) {
+	status, ok := s.statuses[txHash]
+	return status, ok
+}
+
+func (s *stubSender) SendRawTransaction(_ context.Context, input hexutil.Bytes, _ bool, _ ...txsender.SendOption) (txsender.BundleResult, error) {
+	s.calls++
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return txsender.BundleResult{}, err
+	}
+
+	return txsender.BundleResult{TxHash: tx.Hash()}, s.err
+}
+
+func (s *stubSender) SendBundle(context.Context, *types.SendBundleArgs, ...txsender.SendOption) ([]txsender.BuilderResult, error) {
+	s.calls++
+	return nil, s.err
+}
+
+func (s *stubSender) CallBundle(context.Context, *types.SendBundleArgs, ...txsender.SendOption) ([]txsender.BuilderResult, error) {
+	s.calls++
+	return nil, s.err
+}
+
+func (s *stubSender) CancelBundle(context.Context, map[string]builder.BundleID, ...txsender.SendOption) ([]txsender.BuilderResult, error) {
+	s.calls++
+	return nil, s.err
+}
+
+func signedTx(t *testing.T, nonce uint64) hexutil.Bytes {
+	t.Helper()
+
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(56)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(56),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       21000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return input
+}
+
+// call runs a jsonrpc request through the handlers and returns its result or error.
+func call(s *Server, method string, params ...interface{}) (json.RawMessage, *rpc.JsonrpcError) {
+	paramsByte, _ := json.Marshal(params)
+	msg := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":%s}`, method, paramsByte)
+
+	resp := struct {
+		Result json.RawMessage   `json:"result"`
+		Error  *rpc.JsonrpcError `json:"error"`
+	}{}
+	_ = json.Unmarshal(s.handleMessage(withClientIP(context.Background(), "1.1.1.1"), json.RawMessage(msg)), &resp)
+	return resp.Result, resp.Error
+}
+
+func TestSimulationFailureBansSender(t *testing.T) {
+	first, second := signedTx(t, 0), signedTx(t, 1)
+
+	failed := new(types.Transaction)
+	if err := failed.UnmarshalBinary(first); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &stubSender{err: &txsender.SimulationError{Brand: "nodereal", TxHash: failed.Hash(), Reason: "execution reverted"}}
+	s, err := New(Config{Guard: GuardConfig{MaxSimulationFailures: 1}}, "http://127.0.0.1:8545", sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, jrErr := call(s, "eth_sendRawTransaction", first); jrErr == nil {
+		t.Fatal("eth_sendRawTransaction() = nil, want the simulation error")
+	}
+
+	sender.err = nil
+	for _, input := range []hexutil.Bytes{first, second} {
+		if _, jrErr := call(s, "eth_sendRawTransaction", input); jrErr == nil || jrErr.Code != rpc.TransactionRejectedCode {
+			t.Fatalf("eth_sendRawTransaction() after the failure = %v, want a rejection", jrErr)
+		}
+	}
+
+	if _, jrErr := call(s, "eth_callBundle", map[string]interface{}{"txs": []hexutil.Bytes{second}}); jrErr == nil {
+		t.Fatal("eth_callBundle() of a banned sender = nil, want a rejection")
+	}
+
+	if sender.calls != 1 {
+		t.Fatalf("sender calls = %d, want only the first", sender.calls)
+	}
+}
+
+func TestBundleMethodsIPRate(t *testing.T) {
+	bundle := map[string]interface{}{"txs": []hexutil.Bytes{signedTx(t, 0)}}
+	cancel := map[string]interface{}{"bundles": []txsender.BuilderResult{{ID: "flashbots", BundleID: "bundle-1"}}}
+
+	tests := []struct {
+		name   string
+		method string
+		params interface{}
+	}{
+		{"call bundle", "eth_callBundle", bundle},
+		{"cancel bundle", "eth_cancelBundle", cancel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &stubSender{}
+			s, err := New(Config{Guard: GuardConfig{TxPerIP: 1}}, "http://127.0.0.1:8545", sender)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, jrErr := call(s, tt.method, tt.params); jrErr != nil {
+				t.Fatalf("first %s = %v", tt.method, jrErr)
+			}
+
+			if _, jrErr := call(s, tt.method, tt.params); jrErr == nil || jrErr.Code != rpc.TransactionRejectedCode {
+				t.Fatalf("second %s = %v, want the ip rate limit", tt.method, jrErr)
+			}
+
+			if sender.calls != 1 {
+				t.Fatalf("sender calls = %d, want 1", sender.calls)
+			}
+		})
+	}
+}
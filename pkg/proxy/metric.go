@@ -0,0 +1,39 @@
+package proxy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "paymaster"
+
+// subsystem
+const (
+	system = "proxy"
+)
+
+var (
+	RequestCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "request",
+	}, []string{"method", "tenant"})
+
+	ErrorCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "error",
+	}, []string{"method", "tenant"})
+
+	QuotaExceededCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "quota_exceeded",
+	}, []string{"tenant"})
+
+	RejectedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: system,
+		Name:      "rejected",
+	}, []string{"reason"})
+)
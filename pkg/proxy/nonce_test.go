@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func TestGetTransactionCount(t *testing.T) {
+	inputs := []hexutil.Bytes{signedTx(t, 1), signedTx(t, 2)}
+
+	var from common.Address
+	statuses := make(map[common.Hash]txsender.TxStatus)
+	for _, input := range inputs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			t.Fatal(err)
+		}
+
+		from, _ = types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		statuses[tx.Hash()] = txsender.TxStatus{TxHash: tx.Hash(), State: builder.BundlePending}
+	}
+
+	tests := []struct {
+		name          string
+		upstreamNonce string
+		from          common.Address
+		block         string
+		want          string
+	}{
+		{"pending private txs", `"0x1"`, from, "pending", `"0x3"`},
+		{"upstream ahead", `"0x5"`, from, "pending", `"0x5"`},
+		{"latest block", `"0x1"`, from, "latest", `"0x1"`},
+		{"no private tx", `"0x1"`, common.HexToAddress("0x02"), "pending", `"0x1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(Config{}, newUpstream(t, map[string]string{"eth_getTransactionCount": tt.upstreamNonce}), &stubSender{statuses: statuses})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range inputs {
+				if _, jrErr := call(s, "eth_sendRawTransaction", input); jrErr != nil {
+					t.Fatal(jrErr)
+				}
+			}
+
+			got, jrErr := call(s, "eth_getTransactionCount", tt.from, tt.block)
+			if jrErr != nil || string(got) != tt.want {
+				t.Fatalf("eth_getTransactionCount() = %s, %v, want %s", got, jrErr, tt.want)
+			}
+		})
+	}
+}
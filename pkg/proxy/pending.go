@@ -0,0 +1,240 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+const pendingPruneInterval = 3 * time.Second
+
+type pendingTx struct {
+	tx   *types.Transaction
+	from common.Address
+}
+
+// pendingPool keeps the private txs sent through the proxy until they are mined or their
+// bundle window passes, since the public nodes never see them before inclusion.
+type pendingPool struct {
+	mu  sync.RWMutex
+	txs map[common.Hash]*pendingTx
+}
+
+func newPendingPool() *pendingPool {
+	return &pendingPool{txs: make(map[common.Hash]*pendingTx)}
+}
+
+func (p *pendingPool) add(tx *types.Transaction) error {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.txs[tx.Hash()] = &pendingTx{tx: tx, from: from}
+	return nil
+}
+
+func (p *pendingPool) get(txHash common.Hash) (*pendingTx, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	tx, ok := p.txs[txHash]
+	return tx, ok
+}
+
+func (p *pendingPool) remove(txHash common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.txs, txHash)
+}
+
+func (p *pendingPool) hashes() []common.Hash {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	hashes := make([]common.Hash, 0, len(p.txs))
+	for hash := range p.txs {
+		hashes = append(hashes, hash)
+	}
+
+	return hashes
+}
+
+// lookupPending returns the tx if it is still waiting for inclusion, it is dropped from the pool
+// as soon as the sender reports it mined or expired so the upstream node answers from then on.
+func (s *Server) lookupPending(txHash common.Hash) (*pendingTx, bool) {
+	tx, ok := s.pending.get(txHash)
+	if !ok {
+		return nil, false
+	}
+
+	status, ok := s.sender.TxStatus(txHash)
+	if !ok || status.State.Finished() {
+		if status.State == builder.BundleSimulatedFailed {
+			s.guard.recordFailure(txHash, tx.from)
+		}
+
+		s.pending.remove(txHash)
+		return nil, false
+	}
+
+	return tx, true
+}
+
+func (s *Server) prunePending(ctx context.Context) {
+	ticker := time.NewTicker(pendingPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, txHash := range s.pending.hashes() {
+				s.lookupPending(txHash)
+			}
+		}
+	}
+}
+
+func (s *Server) trackPending(input []byte) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		log.Error("failed to unmarshal tx", "err", err)
+		return
+	}
+
+	if err := s.pending.add(tx); err != nil {
+		log.Error("failed to recover tx sender", "tx_hash", tx.Hash(), "err", err)
+	}
+}
+
+func (s *Server) getTransactionByHash(_ context.Context, req *rpc.Request) (interface{}, error) {
+	var txHash common.Hash
+	if err := decodeParams(req, &txHash); err != nil {
+		return nil, err
+	}
+
+	pending, ok := s.lookupPending(txHash)
+	if !ok {
+		return nil, errForward
+	}
+
+	return marshalPendingTx(pending)
+}
+
+// getTransactionReceipt answers null for pending private txs like a node does for its own pending txs.
+func (s *Server) getTransactionReceipt(_ context.Context, req *rpc.Request) (interface{}, error) {
+	var txHash common.Hash
+	if err := decodeParams(req, &txHash); err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.lookupPending(txHash); !ok {
+		return nil, errForward
+	}
+
+	return nil, nil
+}
+
+// marshalPendingTx encodes the tx in the shape of eth_getTransactionByHash with null block fields.
+func marshalPendingTx(pending *pendingTx) (map[string]json.RawMessage, error) {
+	txByte, err := pending.tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := jsoniter.Unmarshal(txByte, &fields); err != nil {
+		return nil, err
+	}
+
+	from, err := jsoniter.Marshal(pending.from)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["from"] = from
+	fields["blockHash"] = json.RawMessage("null")
+	fields["blockNumber"] = json.RawMessage("null")
+	fields["transactionIndex"] = json.RawMessage("null")
+
+	return fields, nil
+}
+
+// nextNonce returns the nonce following the highest pending private tx of the sender.
+func (s *Server) nextNonce(from common.Address) (uint64, bool) {
+	s.pending.mu.RLock()
+	hashes := make([]common.Hash, 0)
+	for hash, tx := range s.pending.txs {
+		if tx.from == from {
+			hashes = append(hashes, hash)
+		}
+	}
+	s.pending.mu.RUnlock()
+
+	nonce, found := uint64(0), false
+	for _, hash := range hashes {
+		pending, ok := s.lookupPending(hash)
+		if !ok {
+			continue
+		}
+
+		if !found || pending.tx.Nonce()+1 > nonce {
+			nonce, found = pending.tx.Nonce()+1, true
+		}
+	}
+
+	return nonce, found
+}
+
+// getTransactionCount accounts the pending private txs in the pending nonce, as the upstream node never saw them.
+func (s *Server) getTransactionCount(ctx context.Context, req *rpc.Request) (interface{}, error) {
+	var (
+		from  common.Address
+		block string
+	)
+	if err := decodeParams(req, &from, &block); err != nil {
+		return nil, errForward
+	}
+
+	if block != "pending" {
+		return nil, errForward
+	}
+
+	nonce, ok := s.nextNonce(from)
+	if !ok {
+		return nil, errForward
+	}
+
+	result, err := s.callUpstream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var upstreamNonce hexutil.Uint64
+	if err := jsoniter.Unmarshal(result, &upstreamNonce); err != nil {
+		log.Error("failed to unmarshal upstream nonce", "from", from, "err", err)
+		return nil, err
+	}
+
+	if uint64(upstreamNonce) > nonce {
+		return upstreamNonce, nil
+	}
+
+	return hexutil.Uint64(nonce), nil
+}
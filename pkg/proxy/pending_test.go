@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+func TestPendingTransaction(t *testing.T) {
+	input := signedTx(t, 0)
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		t.Fatal(err)
+	}
+
+	upstream := newUpstream(t, map[string]string{
+		"eth_getTransactionByHash":  `{"hash":"` + tx.Hash().Hex() + `","blockNumber":"0x10"}`,
+		"eth_getTransactionReceipt": `{"status":"0x1"}`,
+	})
+
+	tests := []struct {
+		name        string
+		state       builder.BundleState
+		wantTx      string
+		wantReceipt string
+	}{
+		{"pending", builder.BundlePending, `"blockNumber":null`, "null"},
+		{"included", builder.BundleIncluded, `"blockNumber":"0x10"`, `{"status":"0x1"}`},
+		{"expired", builder.BundleExpired, `"blockNumber":"0x10"`, `{"status":"0x1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &stubSender{statuses: map[common.Hash]txsender.TxStatus{tx.Hash(): {TxHash: tx.Hash(), State: tt.state}}}
+			s, err := New(Config{}, upstream, sender)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, jrErr := call(s, "eth_sendRawTransaction", input); jrErr != nil {
+				t.Fatal(jrErr)
+			}
+
+			got, jrErr := call(s, "eth_getTransactionByHash", tx.Hash())
+			if jrErr != nil || !json.Valid(got) || !strings.Contains(string(got), tt.wantTx) {
+				t.Fatalf("eth_getTransactionByHash() = %s, %v, want %s", got, jrErr, tt.wantTx)
+			}
+
+			got, jrErr = call(s, "eth_getTransactionReceipt", tx.Hash())
+			if jrErr != nil || string(got) != tt.wantReceipt {
+				t.Fatalf("eth_getTransactionReceipt() = %s, %v, want %s", got, jrErr, tt.wantReceipt)
+			}
+
+			_, pending := s.pending.get(tx.Hash())
+			if pending != !tt.state.Finished() {
+				t.Fatalf("tx in the pool = %v, want %v", pending, !tt.state.Finished())
+			}
+		})
+	}
+}
+
+func TestPendingTransactionFields(t *testing.T) {
+	input := signedTx(t, 7)
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := newPendingPool()
+	if err := pool.add(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, _ := pool.get(tx.Hash())
+	fields, err := marshalPendingTx(pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"hash":             `"` + tx.Hash().Hex() + `"`,
+		"nonce":            `"` + hexutil.EncodeUint64(7) + `"`,
+		"from":             `"` + pending.from.Hex() + `"`,
+		"blockHash":        "null",
+		"blockNumber":      "null",
+		"transactionIndex": "null",
+	}
+
+	for field, value := range want {
+		if got := string(fields[field]); !strings.EqualFold(got, value) {
+			t.Errorf("%s = %s, want %s", field, got, value)
+		}
+	}
+}
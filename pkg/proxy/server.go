@@ -0,0 +1,387 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/policy"
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+type Config struct {
+	ListenAddr  string
+	AllowRevert bool // mark the txs as revertible in the bundles, so they are included even if they revert
+	// Tenants enables api key authentication, the key is taken from the X-Api-Key header or the url path.
+	// They are not reloaded with the builders.
+	Tenants []TenantConfig
+	Guard   GuardConfig
+	// Profile is the txsender profile used by default, /profile/:profile selects another one per request
+	Profile string
+	// TrustedProxies are the ips or cidrs of the reverse proxies whose X-Forwarded-For gives the client ip,
+	// the ip of the connection is used when left empty
+	TrustedProxies []string
+}
+
+const (
+	APIKeyHeader = "X-Api-Key"
+
+	maxBodySize      = 5 << 20 // bytes of a request
+	maxBatchSize     = 100     // requests of a batch
+	batchConcurrency = 16      // requests of a batch handled at once
+	// forwardedMethod labels the metrics of the methods forwarded to the upstream node, the method
+	// names are chosen by the clients
+	forwardedMethod = "forwarded"
+)
+
+// errForward is returned by a handler to hand the request over to the upstream node.
+var errForward = errors.New("forward to upstream")
+
+type handler func(ctx context.Context, req *rpc.Request) (interface{}, error)
+
+// Server is a JSONRPC proxy sending txs privately through txsender.PrivateTxSender
+// and forwarding all other methods to the upstream chain node.
+type Server struct {
+	cfg      Config
+	upstream string
+	sender   txsender.PrivateTxSender
+	handlers map[string]handler
+	engine   *gin.Engine
+	pending  *pendingPool
+	tenants  map[string]*tenant // keyed by api key
+	guard    *guard
+	// builders of the tenants, registered with the sender for their price floors, warmed and closed by Run
+	tenantBuilders []builder.Builder
+}
+
+func New(cfg Config, upstream string, sender txsender.PrivateTxSender) (*Server, error) {
+	s := &Server{
+		cfg:      cfg,
+		upstream: upstream,
+		sender:   sender,
+		pending:  newPendingPool(),
+		tenants:  make(map[string]*tenant),
+		guard:    newGuard(cfg.Guard),
+	}
+
+	for _, tc := range cfg.Tenants {
+		t, err := newTenant(tc)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range tc.APIKeys {
+			s.tenants[key] = t
+		}
+
+		s.tenantBuilders = append(s.tenantBuilders, t.builders...)
+	}
+
+	s.handlers = map[string]handler{
+		"eth_sendRawTransaction":     s.sendRawTransaction,
+		"eth_sendPrivateTransaction": s.sendPrivateTransaction,
+		"eth_getTransactionByHash":   s.getTransactionByHash,
+		"eth_getTransactionReceipt":  s.getTransactionReceipt,
+		"eth_getTransactionCount":    s.getTransactionCount,
+		"eth_sendBundle":             s.sendBundle,
+		"eth_callBundle":             s.callBundle,
+		"eth_cancelBundle":           s.cancelBundle,
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	s.engine = gin.New()
+	if err := s.engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Error("invalid trusted proxies", "err", err)
+		return nil, err
+	}
+
+	s.engine.Use(gin.Recovery())
+	s.engine.POST("/", s.serveJSONRPC)
+	s.engine.POST("/:apikey", s.serveJSONRPC)
+	s.engine.POST("/profile/:profile", s.serveJSONRPC)
+	s.engine.POST("/profile/:profile/:apikey", s.serveJSONRPC)
+
+	return s, nil
+}
+
+// Run serves until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:    s.cfg.ListenAddr,
+		Handler: s.engine,
+	}
+
+	go s.prunePending(ctx)
+
+	if len(s.tenantBuilders) > 0 {
+		s.sender.RegisterBuilders(ctx, s.tenantBuilders)
+		builder.KeepWarm(ctx, s.tenantBuilders)
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = srv.Shutdown(shutdownCtx)
+		s.closeTenantBuilders()
+	}()
+
+	log.Info("proxy server listening", "addr", s.cfg.ListenAddr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("proxy server failed", "err", err)
+		return err
+	}
+
+	return nil
+}
+
+// closeTenantBuilders closes the builders of the tenants holding connections, once the server is shut down.
+func (s *Server) closeTenantBuilders() {
+	for _, b := range s.tenantBuilders {
+		closer, ok := b.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			log.Warn("failed to close tenant builder", "builder", builder.ID(b), "err", err)
+		}
+	}
+}
+
+// authenticate returns the tenant owning the api key of the request, it is nil when no tenants are configured.
+func (s *Server) authenticate(c *gin.Context) (*tenant, bool) {
+	if len(s.tenants) == 0 {
+		return nil, true
+	}
+
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		key = c.Param("apikey")
+	}
+
+	t, ok := s.tenants[key]
+	return t, ok
+}
+
+func (s *Server) serveJSONRPC(c *gin.Context) {
+	t, ok := s.authenticate(c)
+	if !ok {
+		c.Data(http.StatusUnauthorized, gin.MIMEJSON, errorResponse(nil, &rpc.JsonrpcError{Code: rpc.InvalidRequestCode, Message: "invalid api key"}))
+		return
+	}
+
+	ctx := withClientIP(withTenant(c.Request.Context(), t), c.ClientIP())
+	ctx = context.WithValue(ctx, profileKey{}, c.Param("profile"))
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+	if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+		c.Data(http.StatusRequestEntityTooLarge, gin.MIMEJSON, errorResponse(nil, &rpc.JsonrpcError{Code: rpc.InvalidRequestCode, Message: err.Error()}))
+		return
+	}
+
+	if err != nil {
+		c.Data(http.StatusOK, gin.MIMEJSON, errorResponse(nil, &rpc.JsonrpcError{Code: rpc.ParseErrorCode, Message: err.Error()}))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		c.Data(http.StatusOK, gin.MIMEJSON, s.handleMessage(ctx, body))
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := jsoniter.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+		c.Data(http.StatusOK, gin.MIMEJSON, errorResponse(nil, &rpc.JsonrpcError{Code: rpc.InvalidRequestCode, Message: "invalid batch request"}))
+		return
+	}
+
+	if len(batch) > maxBatchSize {
+		c.Data(http.StatusOK, gin.MIMEJSON, errorResponse(nil, &rpc.JsonrpcError{
+			Code:    rpc.InvalidRequestCode,
+			Message: fmt.Sprintf("batch of %d requests exceeds %d", len(batch), maxBatchSize),
+		}))
+		return
+	}
+
+	resps := make([]json.RawMessage, len(batch))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchConcurrency)
+	for idx, msg := range batch {
+		idx, msg := idx, msg
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resps[idx] = s.handleMessage(ctx, msg)
+		}()
+	}
+	wg.Wait()
+
+	respByte, err := jsoniter.Marshal(resps)
+	if err != nil {
+		log.Error("failed to marshal batch response", "err", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Data(http.StatusOK, gin.MIMEJSON, respByte)
+}
+
+func (s *Server) handleMessage(ctx context.Context, msg json.RawMessage) json.RawMessage {
+	req := &rpc.Request{}
+	if err := jsoniter.Unmarshal(msg, req); err != nil {
+		return errorResponse(nil, &rpc.JsonrpcError{Code: rpc.ParseErrorCode, Message: err.Error()})
+	}
+
+	tenantName := tenantFromContext(ctx).name()
+	RequestCounter.WithLabelValues(s.methodLabel(req.Method), tenantName).Inc()
+
+	h, ok := s.handlers[req.Method]
+	if !ok {
+		return s.forward(ctx, req, msg)
+	}
+
+	result, err := h(ctx, req)
+	if errors.Is(err, errForward) {
+		return s.forward(ctx, req, msg)
+	}
+
+	if err != nil {
+		ErrorCounter.WithLabelValues(s.methodLabel(req.Method), tenantName).Inc()
+
+		jrError := &rpc.JsonrpcError{}
+		violation := &policy.Violation{}
+		switch {
+		case errors.As(err, &jrError):
+		case errors.As(err, &violation):
+			jrError = &rpc.JsonrpcError{Code: rpc.TransactionRejectedCode, Message: violation.Error(), Data: violation}
+		default:
+			jrError = &rpc.JsonrpcError{Code: rpc.InternalErrorCode, Message: err.Error()}
+		}
+
+		return errorResponse(req.ID, jrError)
+	}
+
+	resultByte, err := jsoniter.Marshal(result)
+	if err != nil {
+		log.Error("failed to marshal jsonrpc result", "method", req.Method, "err", err)
+		return errorResponse(req.ID, &rpc.JsonrpcError{Code: rpc.InternalErrorCode, Message: err.Error()})
+	}
+
+	resp, _ := jsoniter.Marshal(&rpc.Response{
+		ID:      responseID(req.ID),
+		JSONRPC: "2.0",
+		Result:  resultByte,
+	})
+
+	return resp
+}
+
+// methodLabel keeps the metric labels bounded, only the methods handled by the proxy are named.
+func (s *Server) methodLabel(method string) string {
+	if _, ok := s.handlers[method]; ok {
+		return method
+	}
+
+	return forwardedMethod
+}
+
+// forward sends the original message to the upstream node and returns its response untouched.
+func (s *Server) forward(ctx context.Context, req *rpc.Request, msg json.RawMessage) json.RawMessage {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.upstream, bytes.NewReader(msg))
+	if err != nil {
+		log.Error("failed to create upstream request", "method", req.Method, "err", err)
+		return errorResponse(req.ID, &rpc.JsonrpcError{Code: rpc.InternalErrorCode, Message: err.Error()})
+	}
+
+	httpReq.Header.Set("Content-Type", gin.MIMEJSON)
+
+	httpResp, err := rpc.HTTPClient.Do(httpReq)
+	if err != nil {
+		ErrorCounter.WithLabelValues(s.methodLabel(req.Method), tenantFromContext(ctx).name()).Inc()
+
+		log.Error("failed to forward request to upstream", "method", req.Method, "err", err)
+		return errorResponse(req.ID, &rpc.JsonrpcError{Code: rpc.InternalErrorCode, Message: "upstream unavailable"})
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil || !rpc.HTTPCode(httpResp.StatusCode).Success() {
+		ErrorCounter.WithLabelValues(s.methodLabel(req.Method), tenantFromContext(ctx).name()).Inc()
+
+		log.Error("failed to read upstream response", "method", req.Method, "code", httpResp.StatusCode, "err", err)
+		return errorResponse(req.ID, &rpc.JsonrpcError{
+			Code:    rpc.InternalErrorCode,
+			Message: fmt.Sprintf("upstream error, code: %d", httpResp.StatusCode),
+		})
+	}
+
+	return bytes.TrimSpace(body)
+}
+
+// callUpstream sends the request to the upstream node and returns the result.
+func (s *Server) callUpstream(ctx context.Context, req *rpc.Request) (json.RawMessage, error) {
+	upstreamReq := *req
+	upstreamReq.ID = json.RawMessage("1") // client ids may be strings, JsonrpcResponse only takes numbers
+
+	msg, err := jsoniter.Marshal(&upstreamReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := rpc.JsonrpcResponse{}
+	if err := jsoniter.Unmarshal(s.forward(ctx, req, msg), &resp); err != nil {
+		log.Error("failed to unmarshal upstream response", "method", req.Method, "err", err)
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		jrError := &rpc.JsonrpcError{}
+		if err := jsoniter.Unmarshal(*resp.Error, jrError); err != nil {
+			return nil, err
+		}
+
+		return nil, jrError
+	}
+
+	return resp.Result, nil
+}
+
+func errorResponse(id json.RawMessage, jrError *rpc.JsonrpcError) json.RawMessage {
+	resp, _ := jsoniter.Marshal(&rpc.Response{
+		ID:      responseID(id),
+		JSONRPC: "2.0",
+		Error:   jrError,
+	})
+
+	return resp
+}
+
+func responseID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+
+	return id
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newUpstream answers each method with its raw result and a method not found error otherwise.
+func newUpstream(t *testing.T, results map[string]string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if result, ok := results[req.Method]; ok {
+			resp["result"] = json.RawMessage(result)
+		} else {
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func TestServeJSONRPC(t *testing.T) {
+	batch := func(n int) string {
+		reqs := make([]string, n)
+		for i := range reqs {
+			reqs[i] = `{"jsonrpc":"2.0","id":` + strings.Repeat("1", i+1) + `,"method":"eth_chainId"}`
+		}
+		return "[" + strings.Join(reqs, ",") + "]"
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		want     string
+	}{
+		{"single", `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`, http.StatusOK, `"result":"0x38"`},
+		{"batch keeps the order", batch(3), http.StatusOK, `[{"id":1,"jsonrpc":"2.0","result":"0x38"},{"id":11,"jsonrpc":"2.0","result":"0x38"},{"id":111,"jsonrpc":"2.0","result":"0x38"}]`},
+		{"batch too large", batch(maxBatchSize + 1), http.StatusOK, "exceeds"},
+		{"body too large", `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":["` + strings.Repeat("0", maxBodySize) + `"]}`,
+			http.StatusRequestEntityTooLarge, "too large"},
+	}
+
+	s, err := New(Config{}, newUpstream(t, map[string]string{"eth_chainId": `"0x38"`}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body)))
+
+			if w.Code != tt.wantCode || !strings.Contains(w.Body.String(), tt.want) {
+				t.Fatalf("response = %d %s, want %d containing %s", w.Code, w.Body.String(), tt.wantCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestMethodLabel(t *testing.T) {
+	s, err := New(Config{}, "http://127.0.0.1:8545", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"eth_sendRawTransaction", "eth_sendRawTransaction"},
+		{"eth_chainId", forwardedMethod},
+		{"random_" + strings.Repeat("x", 64), forwardedMethod},
+	}
+
+	for _, tt := range tests {
+		if got := s.methodLabel(tt.method); got != tt.want {
+			t.Errorf("methodLabel(%q) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+	"github.com/node-real/private-tx-sender/pkg/txsender"
+)
+
+// TenantConfig describes a client of the proxy, a zero quota means unlimited and an empty
+// builder list means the shared builders of the sender are used.
+type TenantConfig struct {
+	Name        string
+	APIKeys     []string
+	TxPerMinute uint64
+	TxPerDay    uint64
+	Builders    []builder.Config // builders with the tenant's own keys
+	Profile     string           // overrides the default profile of the proxy
+}
+
+type tenant struct {
+	cfg      TenantConfig
+	builders []builder.Builder
+
+	mu          sync.Mutex
+	minute      int64
+	minuteCount uint64
+	day         int64
+	dayCount    uint64
+}
+
+func newTenant(cfg TenantConfig) (*tenant, error) {
+	builders, err := builder.NewAll(cfg.Builders)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+	}
+
+	return &tenant{cfg: cfg, builders: builders}, nil
+}
+
+// take counts n txs against the quotas of the tenant, nothing is counted if any quota is exceeded.
+func (t *tenant) take(now time.Time, n uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minute, day := now.Unix()/60, now.UTC().Unix()/86400
+	if minute != t.minute {
+		t.minute, t.minuteCount = minute, 0
+	}
+
+	if day != t.day {
+		t.day, t.dayCount = day, 0
+	}
+
+	if t.cfg.TxPerMinute > 0 && t.minuteCount+n > t.cfg.TxPerMinute {
+		return &rpc.JsonrpcError{Code: rpc.LimitExceededCode, Message: fmt.Sprintf("quota of %d txs per minute exceeded", t.cfg.TxPerMinute)}
+	}
+
+	if t.cfg.TxPerDay > 0 && t.dayCount+n > t.cfg.TxPerDay {
+		return &rpc.JsonrpcError{Code: rpc.LimitExceededCode, Message: fmt.Sprintf("quota of %d txs per day exceeded", t.cfg.TxPerDay)}
+	}
+
+	t.minuteCount += n
+	t.dayCount += n
+	return nil
+}
+
+// sendOptions routes the sends of the request to the builders of its tenant if it has any,
+// the profile is picked from the url path, then the tenant and then the proxy config.
+func (s *Server) sendOptions(ctx context.Context) []txsender.SendOption {
+	options := make([]txsender.SendOption, 0, 2)
+
+	profile := s.cfg.Profile
+	if t := tenantFromContext(ctx); t != nil {
+		if len(t.builders) > 0 {
+			options = append(options, txsender.WithBuilders(t.builders))
+		}
+
+		if t.cfg.Profile != "" {
+			profile = t.cfg.Profile
+		}
+	}
+
+	if name, _ := ctx.Value(profileKey{}).(string); name != "" {
+		profile = name
+	}
+
+	return append(options, txsender.WithProfile(profile))
+}
+
+type profileKey struct{}
+
+func (t *tenant) name() string {
+	if t == nil {
+		return ""
+	}
+
+	return t.cfg.Name
+}
+
+type tenantKey struct{}
+
+func withTenant(ctx context.Context, t *tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// tenantFromContext returns the tenant of the request, nil when the proxy runs without tenants.
+func tenantFromContext(ctx context.Context) *tenant {
+	t, _ := ctx.Value(tenantKey{}).(*tenant)
+	return t
+}
+
+// takeQuota counts n txs sent by the tenant of the request.
+func takeQuota(ctx context.Context, n uint64) error {
+	t := tenantFromContext(ctx)
+	if t == nil {
+		return nil
+	}
+
+	if err := t.take(time.Now(), n); err != nil {
+		QuotaExceededCounter.WithLabelValues(t.name()).Inc()
+		return err
+	}
+
+	return nil
+}
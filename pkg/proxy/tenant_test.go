@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/node-real/private-tx-sender/pkg/rpc"
+)
+
+func TestTenantTake(t *testing.T) {
+	start := time.Date(2024, 5, 1, 23, 58, 30, 0, time.UTC)
+
+	type take struct {
+		at      time.Duration // since start
+		n       uint64
+		wantErr bool
+	}
+
+	tests := []struct {
+		name  string
+		cfg   TenantConfig
+		takes []take
+	}{
+		{"unlimited", TenantConfig{}, []take{{0, 100, false}, {0, 100, false}}},
+		{"minute quota", TenantConfig{TxPerMinute: 2}, []take{{0, 1, false}, {time.Second, 1, false}, {2 * time.Second, 1, true}}},
+		{"bundle counted whole", TenantConfig{TxPerMinute: 2}, []take{{0, 1, false}, {0, 2, true}, {0, 1, false}}},
+		{"next minute", TenantConfig{TxPerMinute: 1}, []take{{0, 1, false}, {10 * time.Second, 1, true}, {40 * time.Second, 1, false}}},
+		{"day quota across minutes", TenantConfig{TxPerDay: 2}, []take{{0, 1, false}, {time.Minute, 1, false}, {time.Minute, 1, true}}},
+		{"next utc day", TenantConfig{TxPerDay: 1}, []take{{0, 1, false}, {time.Minute, 1, true}, {2 * time.Minute, 1, false}}},
+		{"rejected take not counted", TenantConfig{TxPerMinute: 5, TxPerDay: 2}, []take{{0, 3, true}, {0, 2, false}, {0, 1, true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tn := &tenant{cfg: tt.cfg}
+			for i, take := range tt.takes {
+				err := tn.take(start.Add(take.at), take.n)
+				if (err != nil) != take.wantErr {
+					t.Fatalf("take #%d = %v, want error %v", i, err, take.wantErr)
+				}
+
+				var jrErr *rpc.JsonrpcError
+				if err != nil && (!errors.As(err, &jrErr) || jrErr.Code != rpc.LimitExceededCode) {
+					t.Fatalf("take #%d = %v, want a limit exceeded error", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	tenants := []TenantConfig{
+		{Name: "acme", APIKeys: []string{"key-1", "key-2"}},
+		{Name: "globex", APIKeys: []string{"key-3"}},
+	}
+
+	tests := []struct {
+		name       string
+		tenants    []TenantConfig
+		header     string
+		pathKey    string
+		wantOK     bool
+		wantTenant string
+	}{
+		{"no tenants", nil, "", "", true, ""},
+		{"header key", tenants, "key-2", "", true, "acme"},
+		{"path key", tenants, "", "key-3", true, "globex"},
+		{"header before path", tenants, "key-1", "key-3", true, "acme"},
+		{"unknown key", tenants, "key-4", "", false, ""},
+		{"missing key", tenants, "", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(Config{Tenants: tt.tenants}, "http://127.0.0.1:8545", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(APIKeyHeader, tt.header)
+			}
+			if tt.pathKey != "" {
+				c.Params = gin.Params{{Key: "apikey", Value: tt.pathKey}}
+			}
+
+			tn, ok := s.authenticate(c)
+			if ok != tt.wantOK || tn.name() != tt.wantTenant {
+				t.Fatalf("authenticate() = %q, %v, want %q, %v", tn.name(), ok, tt.wantTenant, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/policy"
+)
+
+type Dispatch string
+
+const (
+	// DispatchFirst returns as soon as one builder accepts the bundle, the default
+	DispatchFirst Dispatch = "first"
+	// DispatchAll waits for every builder before returning
+	DispatchAll Dispatch = "all"
+)
+
+// Rule matches a tx when every non empty condition holds, a condition listing several values matches any of them.
+type Rule struct {
+	Name      string
+	To        []common.Address
+	Selectors []policy.Selector
+	Senders   []common.Address
+	MinValue  *big.Int
+	MaxValue  *big.Int
+
+	Builders []builder.Brand // empty means all builders
+	Dispatch Dispatch
+}
+
+type Config struct {
+	Rules []Rule
+}
+
+// Route is the builder subset and dispatch policy picked for a tx.
+type Route struct {
+	Rule     string // empty if no rule matched
+	Builders []builder.Builder
+	Dispatch Dispatch
+}
+
+type Router struct {
+	rules []Rule
+}
+
+func New(cfg Config) *Router {
+	return &Router{rules: cfg.Rules}
+}
+
+// Route returns the route of the first rule matching the tx, txs matching no rule go to all builders.
+func (r *Router) Route(tx *types.Transaction, from common.Address, builders []builder.Builder) Route {
+	for _, rule := range r.rules {
+		if !rule.match(tx, from) {
+			continue
+		}
+
+		route := Route{
+			Rule:     rule.Name,
+			Builders: rule.filter(builders),
+			Dispatch: rule.Dispatch,
+		}
+
+		if route.Dispatch == "" {
+			route.Dispatch = DispatchFirst
+		}
+
+		return route
+	}
+
+	return Route{Builders: builders, Dispatch: DispatchFirst}
+}
+
+func (r *Rule) match(tx *types.Transaction, from common.Address) bool {
+	if len(r.To) > 0 && (tx.To() == nil || !contains(r.To, *tx.To())) {
+		return false
+	}
+
+	if len(r.Senders) > 0 && !contains(r.Senders, from) {
+		return false
+	}
+
+	if len(r.Selectors) > 0 {
+		var selector policy.Selector
+		if len(tx.Data()) < len(selector) {
+			return false
+		}
+
+		copy(selector[:], tx.Data())
+		if !contains(r.Selectors, selector) {
+			return false
+		}
+	}
+
+	if r.MinValue != nil && tx.Value().Cmp(r.MinValue) < 0 {
+		return false
+	}
+
+	if r.MaxValue != nil && tx.Value().Cmp(r.MaxValue) > 0 {
+		return false
+	}
+
+	return true
+}
+
+func (r *Rule) filter(builders []builder.Builder) []builder.Builder {
+	if len(r.Builders) == 0 {
+		return builders
+	}
+
+	filtered := make([]builder.Builder, 0, len(builders))
+	for _, b := range builders {
+		if contains(r.Builders, builder.Brand(b.GetBrand())) {
+			filtered = append(filtered, b)
+		}
+	}
+
+	return filtered
+}
+
+func contains[T comparable](items []T, item T) bool {
+	for _, v := range items {
+		if v == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/policy"
+)
+
+type stubBuilder struct {
+	brand builder.Brand
+}
+
+func (b stubBuilder) SendBundle(context.Context, *types.SendBundleArgs, uint64) (builder.BundleID, error) {
+	return "", nil
+}
+
+func (b stubBuilder) GetBrand() string {
+	return string(b.brand)
+}
+
+func TestRoute(t *testing.T) {
+	var (
+		sender = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		other  = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		dex    = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		token  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		swap   = policy.Selector{0x38, 0xed, 0x17, 0x39}
+	)
+
+	builders := []builder.Builder{
+		stubBuilder{builder.Nodereal},
+		stubBuilder{builder.Blockrazor},
+		stubBuilder{builder.Bloxroute},
+	}
+
+	rules := []Rule{
+		{Name: "swaps", To: []common.Address{dex}, Selectors: []policy.Selector{swap}, Builders: []builder.Brand{builder.Blockrazor}, Dispatch: DispatchAll},
+		{Name: "whales", MinValue: big.NewInt(100), Builders: []builder.Brand{builder.Nodereal, builder.Bloxroute}},
+		{Name: "desk", Senders: []common.Address{sender}, MaxValue: big.NewInt(10), Builders: []builder.Brand{builder.Bloxroute}},
+		{Name: "unknown brand", To: []common.Address{token}, Builders: []builder.Brand{builder.Txboost}},
+	}
+
+	newTx := func(to *common.Address, value int64, data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: to, Value: big.NewInt(value), GasPrice: big.NewInt(1), Gas: 21000, Data: data})
+	}
+
+	tests := []struct {
+		name         string
+		tx           *types.Transaction
+		from         common.Address
+		wantRule     string
+		wantBrands   []builder.Brand
+		wantDispatch Dispatch
+	}{
+		{"swap", newTx(&dex, 0, append(swap[:], 1)), other, "swaps", []builder.Brand{builder.Blockrazor}, DispatchAll},
+		{"other selector to the dex", newTx(&dex, 0, []byte{1, 2, 3, 4}), other, "", []builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}, DispatchFirst},
+		{"short calldata to the dex", newTx(&dex, 0, swap[:2]), other, "", []builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}, DispatchFirst},
+		{"min value", newTx(&token, 100, nil), other, "whales", []builder.Brand{builder.Nodereal, builder.Bloxroute}, DispatchFirst},
+		{"sender within max value", newTx(&dex, 10, nil), sender, "desk", []builder.Brand{builder.Bloxroute}, DispatchFirst},
+		{"sender above max value", newTx(&dex, 11, nil), sender, "", []builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}, DispatchFirst},
+		{"contract creation", newTx(nil, 0, nil), other, "", []builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}, DispatchFirst},
+		{"no builder of the rule", newTx(&token, 0, nil), other, "unknown brand", nil, DispatchFirst},
+	}
+
+	r := New(Config{Rules: rules})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := r.Route(tt.tx, tt.from, builders)
+			if route.Rule != tt.wantRule || route.Dispatch != tt.wantDispatch {
+				t.Fatalf("Route() = rule %q dispatch %q, want %q %q", route.Rule, route.Dispatch, tt.wantRule, tt.wantDispatch)
+			}
+
+			if len(route.Builders) != len(tt.wantBrands) {
+				t.Fatalf("Route() = %d builders, want %v", len(route.Builders), tt.wantBrands)
+			}
+
+			for i, b := range route.Builders {
+				if builder.Brand(b.GetBrand()) != tt.wantBrands[i] {
+					t.Errorf("Route().Builders[%d] = %s, want %s", i, b.GetBrand(), tt.wantBrands[i])
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package rpc
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -14,7 +16,7 @@ var (
 	}
 
 	transport = &http.Transport{
-		DialContext:         dialer.DialContext,
+		DialContext:         dialContext,
 		MaxIdleConnsPerHost: 2000,
 		MaxConnsPerHost:     2000,
 		IdleConnTimeout:     90 * time.Second,
@@ -27,6 +29,46 @@ var (
 	}
 )
 
+// pinnedHosts maps a host to the addresses resolved by PinHost.
+var pinnedHosts sync.Map
+
+// PinHost resolves the host now, the new connections to it then dial the resolved addresses
+// without a dns lookup. Calling it again refreshes the addresses, a failed lookup keeps them.
+func PinHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	pinnedHosts.Store(host, addrs)
+	return nil
+}
+
+// UnpinHost makes the connections to the host resolve it again.
+func UnpinHost(host string) {
+	pinnedHosts.Delete(host)
+}
+
+// dialContext dials the pinned addresses of the host first and falls back to a lookup when none
+// of them accepts the connection.
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	if addrs, ok := pinnedHosts.Load(host); ok {
+		for _, addr := range addrs.([]string) {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
 type HTTPCode int
 
 func (code HTTPCode) Success() bool {
@@ -35,6 +77,7 @@ func (code HTTPCode) Success() bool {
 
 type CallOptions struct {
 	Header map[string]string
+	Signer RequestSigner // adds the headers signing the request body
 }
 
 func (c *CallOptions) ApplyOptions(options ...CallOption) {
@@ -52,3 +95,9 @@ func WithHeader(header map[string]string) CallOption {
 		}
 	}
 }
+
+func WithSigner(signer RequestSigner) CallOption {
+	return func(c *CallOptions) {
+		c.Signer = signer
+	}
+}
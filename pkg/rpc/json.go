@@ -6,7 +6,14 @@ import (
 )
 
 var (
-	InternalErrorCode = -32603
+	ParseErrorCode     = -32700
+	InvalidRequestCode = -32600
+	MethodNotFoundCode = -32601
+	InvalidParamsCode  = -32602
+	InternalErrorCode  = -32603
+	LimitExceededCode  = -32005
+
+	TransactionRejectedCode = -32003
 )
 
 type Param json.RawMessage
@@ -51,3 +58,24 @@ type JsonrpcError struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+func (e *JsonrpcError) Error() string {
+	return e.Message
+}
+
+// Request is meant to be used to deserialize JSONRPC requests from our own clients,
+// the id is kept unparsed since clients may use numbers or strings.
+type Request struct {
+	ID      json.RawMessage `json:"id,omitempty"`
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  Params          `json:"params"`
+}
+
+// Response is meant to be used to craft our own responses to clients.
+type Response struct {
+	ID      json.RawMessage `json:"id"`
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JsonrpcError   `json:"error,omitempty"`
+}
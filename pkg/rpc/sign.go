@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const FlashbotsSignatureHeader = "X-Flashbots-Signature"
+
+// RequestSigner returns the headers authenticating the request body, it is called with the
+// exact bytes that are sent.
+type RequestSigner func(body []byte) (map[string]string, error)
+
+// FlashbotsSigner signs the body with the key as flashbots does: the EIP-191 signature of the
+// hex encoded keccak256 of the body, sent as "<address>:<signature>".
+func FlashbotsSigner(key *ecdsa.PrivateKey) RequestSigner {
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	return func(body []byte) (map[string]string, error) {
+		hash := hexutil.Encode(crypto.Keccak256(body))
+		sig, err := crypto.Sign(accounts.TextHash([]byte(hash)), key)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]string{FlashbotsSignatureHeader: address + ":" + hexutil.Encode(sig)}, nil
+	}
+}
@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestFlashbotsSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[{"txs":[],"blockNumber":"0x1"}]}`)
+	header, err := FlashbotsSigner(key)(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address, sig, ok := strings.Cut(header[FlashbotsSignatureHeader], ":")
+	if !ok {
+		t.Fatalf("%s = %q, want <address>:<signature>", FlashbotsSignatureHeader, header[FlashbotsSignatureHeader])
+	}
+
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	if address != signer.Hex() {
+		t.Fatalf("address = %s, want %s", address, signer.Hex())
+	}
+
+	sigByte, err := hexutil.Decode(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := accounts.TextHash([]byte(hexutil.Encode(crypto.Keccak256(body))))
+	pub, err := crypto.SigToPub(hash, sigByte)
+	if err != nil || crypto.PubkeyToAddress(*pub) != signer {
+		t.Fatalf("signature does not recover the signer, err %v", err)
+	}
+}
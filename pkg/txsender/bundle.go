@@ -0,0 +1,243 @@
+package txsender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+var (
+	ErrEmptyBundle   = errors.New("empty bundle")
+	ErrBundleExpired = errors.New("bundle max block number already passed")
+	ErrNotSupported  = errors.New("not supported by builder")
+	ErrUnknownID     = errors.New("unknown builder id")
+)
+
+// BuilderResult is the outcome of a bundle call on a single builder, ID is the builder.ID of the
+// builder and identifies it when cancelling, also after the builders are reloaded.
+type BuilderResult struct {
+	ID       string           `json:"builderId"`
+	Brand    string           `json:"builder"`
+	BundleID builder.BundleID `json:"bundleId,omitempty"`
+	Result   json.RawMessage  `json:"result,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// SendBundle sends the bundle to the builders selected for it like for a tx: the route of its first
+// tx, the validators of the window and the price floors met by the gas weighted price of the bundle.
+// It waits for every answer and tracks the bundle by its first tx. A zero MaxBlockNumber is replaced
+// by the end of the bundle window of the profile, args are not modified.
+func (s *privateTxSender) SendBundle(ctx context.Context, args *types.SendBundleArgs, options ...SendOption) ([]BuilderResult, error) {
+	opt := s.sendOptions(options...)
+	profile, err := s.profile(opt.Profile)
+	if err != nil {
+		log.Error("failed to load profile", "err", err)
+		return nil, err
+	}
+
+	if len(args.Txs) == 0 {
+		return nil, ErrEmptyBundle
+	}
+
+	args = copyBundleArgs(args)
+
+	latestHeader := s.latestHeader.Load()
+	latestNumber := latestHeader.Number.Uint64()
+	if args.MaxBlockNumber == 0 {
+		args.MaxBlockNumber = latestNumber + profile.bundleLifeNumber(s.cfg)
+	}
+
+	if args.MaxBlockNumber <= latestNumber {
+		return nil, fmt.Errorf("%w: %d", ErrBundleExpired, args.MaxBlockNumber)
+	}
+
+	txs, leadFrom, err := s.evaluateBundle(args)
+	if err != nil {
+		return nil, err
+	}
+
+	lead := txs[0]
+	_, builders, err := s.selectBuilders(lead, leadFrom, bundleGasPrice(txs, latestHeader), profile, opt.Builders, args, latestHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.Simulate {
+		if err := s.simulate(ctx, builders, args); err != nil {
+			log.Warn("bundle rejected by simulation", "tx_hash", lead.Hash(), "err", err)
+			return nil, err
+		}
+	}
+
+	s.tracker.add(lead.Hash(), args, latestNumber+1)
+
+	bundleLifeNumber := args.MaxBlockNumber - latestNumber
+
+	accepted := false
+	results := runForAll(builders, func(b builder.Builder) BuilderResult {
+		id := builder.ID(b)
+		bundleID, err := b.SendBundle(ctx, args, bundleLifeNumber)
+		if err != nil {
+			log.Error("send bundle to builder failed", "builder", id, "tx_hash", lead.Hash(), "err", err)
+			return BuilderResult{ID: id, Brand: b.GetBrand(), Error: err.Error()}
+		}
+
+		s.tracker.addBundle(b, BundleResult{TxHash: lead.Hash(), ID: id, Brand: b.GetBrand(), BundleID: bundleID})
+		return BuilderResult{ID: id, Brand: b.GetBrand(), BundleID: bundleID}
+	})
+
+	for _, result := range results {
+		accepted = accepted || result.Error == ""
+	}
+
+	if !accepted {
+		s.tracker.remove(lead.Hash())
+	}
+
+	return results, nil
+}
+
+// evaluateBundle decodes the txs of the bundle and runs the policy on each of them, it returns the
+// txs and the sender of the first one.
+func (s *privateTxSender) evaluateBundle(args *types.SendBundleArgs) ([]*types.Transaction, common.Address, error) {
+	txs := make([]*types.Transaction, 0, len(args.Txs))
+	var leadFrom common.Address
+	for idx, input := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			log.Error("failed to unmarshal tx", "err", err)
+			return nil, common.Address{}, err
+		}
+
+		from, err := s.evaluatePolicy(tx)
+		if err != nil {
+			log.Warn("bundle rejected by policy", "tx_hash", tx.Hash(), "err", err)
+			return nil, common.Address{}, err
+		}
+
+		if idx == 0 {
+			leadFrom = from
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return txs, leadFrom, nil
+}
+
+// CallBundle simulates the bundle for the next block on the builders implementing builder.BundleSimulator,
+// the txs are checked by the policy first as for SendBundle.
+func (s *privateTxSender) CallBundle(ctx context.Context, args *types.SendBundleArgs, options ...SendOption) ([]BuilderResult, error) {
+	if len(args.Txs) == 0 {
+		return nil, ErrEmptyBundle
+	}
+
+	if _, _, err := s.evaluateBundle(args); err != nil {
+		return nil, err
+	}
+
+	args = copyBundleArgs(args)
+	args.MaxBlockNumber = s.latestHeader.Load().Number.Uint64() + 1
+
+	opt := s.sendOptions(options...)
+
+	return runForAll(opt.Builders, func(b builder.Builder) BuilderResult {
+		simulator, ok := b.(builder.BundleSimulator)
+		if !ok {
+			return BuilderResult{ID: builder.ID(b), Brand: b.GetBrand(), Error: ErrNotSupported.Error()}
+		}
+
+		result, err := simulator.CallBundle(ctx, args)
+		if err != nil {
+			return BuilderResult{ID: builder.ID(b), Brand: b.GetBrand(), Error: err.Error()}
+		}
+
+		return BuilderResult{ID: builder.ID(b), Brand: b.GetBrand(), Result: result}
+	}), nil
+}
+
+// CancelBundle cancels the bundles keyed by the ID of the builder returned by SendBundle on the
+// builders implementing builder.BundleCanceller, an id matching none of the builders is reported
+// with ErrUnknownID.
+func (s *privateTxSender) CancelBundle(ctx context.Context, bundleIDs map[string]builder.BundleID, options ...SendOption) ([]BuilderResult, error) {
+	candidates := s.sendOptions(options...).Builders
+
+	builders := make([]builder.Builder, 0, len(bundleIDs))
+	matched := make(map[string]bool, len(bundleIDs))
+	for _, b := range candidates {
+		if id := builder.ID(b); !matched[id] {
+			if _, ok := bundleIDs[id]; ok {
+				builders = append(builders, b)
+				matched[id] = true
+			}
+		}
+	}
+
+	results := runForAll(builders, func(b builder.Builder) BuilderResult {
+		id := builder.ID(b)
+		bundleID := bundleIDs[id]
+
+		canceller, ok := b.(builder.BundleCanceller)
+		if !ok {
+			return BuilderResult{ID: id, Brand: b.GetBrand(), BundleID: bundleID, Error: ErrNotSupported.Error()}
+		}
+
+		if err := canceller.CancelBundle(ctx, bundleID); err != nil {
+			return BuilderResult{ID: id, Brand: b.GetBrand(), BundleID: bundleID, Error: err.Error()}
+		}
+
+		return BuilderResult{ID: id, Brand: b.GetBrand(), BundleID: bundleID}
+	})
+
+	unknown := make([]string, 0, len(bundleIDs)-len(matched))
+	for id := range bundleIDs {
+		if !matched[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, id := range unknown {
+		results = append(results, BuilderResult{ID: id, BundleID: bundleIDs[id], Error: ErrUnknownID.Error()})
+	}
+
+	return results, nil
+}
+
+// copyBundleArgs copies the args and their slices, so that the bundle sent and tracked does not
+// change with the args of the caller.
+func copyBundleArgs(args *types.SendBundleArgs) *types.SendBundleArgs {
+	c := *args
+	c.Txs = append([]hexutil.Bytes(nil), args.Txs...)
+	c.RevertingTxHashes = append([]common.Hash(nil), args.RevertingTxHashes...)
+	return &c
+}
+
+// runForAll runs the task on every builder in parallel and returns the results in the order of builders.
+func runForAll(builders []builder.Builder, task func(b builder.Builder) BuilderResult) []BuilderResult {
+	results := make([]BuilderResult, len(builders))
+
+	var wg sync.WaitGroup
+	for idx, b := range builders {
+		idx, b := idx, b
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[idx] = task(b)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,141 @@
+package txsender
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/policy"
+)
+
+type stubCanceller struct {
+	stubBuilder
+	cancelled []builder.BundleID
+}
+
+func (b *stubCanceller) CancelBundle(_ context.Context, id builder.BundleID) error {
+	b.cancelled = append(b.cancelled, id)
+	return nil
+}
+
+func TestCancelBundleByID(t *testing.T) {
+	first := &stubCanceller{stubBuilder: stubBuilder{"flashbots"}}
+	second := &stubCanceller{stubBuilder: stubBuilder{"titan"}}
+	builders := []builder.Builder{first, second}
+
+	s := &privateTxSender{}
+	s.builders.Store(&builders)
+
+	results, err := s.CancelBundle(context.Background(), map[string]builder.BundleID{"flashbots": "a", "titan": "b", "removed": "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 || results[2].ID != "removed" || results[2].Error != ErrUnknownID.Error() {
+		t.Fatalf("CancelBundle() = %+v, want 2 cancelled and the unknown id last", results)
+	}
+
+	if len(first.cancelled) != 1 || first.cancelled[0] != "a" || len(second.cancelled) != 1 || second.cancelled[0] != "b" {
+		t.Errorf("cancelled %v and %v, want [a] and [b]", first.cancelled, second.cancelled)
+	}
+}
+
+type stubSimulator struct {
+	stubBuilder
+	calls int
+}
+
+func (b *stubSimulator) CallBundle(context.Context, *types.SendBundleArgs) (json.RawMessage, error) {
+	b.calls++
+	return json.RawMessage(`{"results":[]}`), nil
+}
+
+func TestCallBundlePolicy(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := types.LatestSignerForChainID(big.NewInt(56))
+	bundle := func(value int64) *types.SendBundleArgs {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Value: big.NewInt(value), GasPrice: big.NewInt(1), Gas: 21000})
+		input, _ := tx.MarshalBinary()
+		return &types.SendBundleArgs{Txs: []hexutil.Bytes{input}}
+	}
+
+	tests := []struct {
+		name      string
+		args      *types.SendBundleArgs
+		wantErr   bool
+		wantCalls int
+	}{
+		{"allowed", bundle(10), false, 1},
+		{"policy violation", bundle(11), true, 0},
+		{"empty bundle", &types.SendBundleArgs{}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulator := &stubSimulator{stubBuilder: stubBuilder{"nodereal"}}
+			builders := []builder.Builder{simulator}
+
+			s := &privateTxSender{policy: policy.New(policy.Config{MaxValue: big.NewInt(10)})}
+			s.builders.Store(&builders)
+			s.latestHeader.Store(&types.Header{Number: big.NewInt(100)})
+
+			_, err := s.CallBundle(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr || simulator.calls != tt.wantCalls {
+				t.Fatalf("CallBundle() = %v with %d simulations, want error %v with %d", err, simulator.calls, tt.wantErr, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCopyBundleArgs(t *testing.T) {
+	args := &types.SendBundleArgs{
+		Txs:               []hexutil.Bytes{{0x01}},
+		RevertingTxHashes: []common.Hash{{0x02}},
+	}
+
+	c := copyBundleArgs(args)
+	c.MaxBlockNumber = 10
+	c.Txs[0] = hexutil.Bytes{0x03}
+	c.RevertingTxHashes[0] = common.Hash{0x04}
+
+	if args.MaxBlockNumber != 0 || args.Txs[0][0] != 0x01 || args.RevertingTxHashes[0] != (common.Hash{0x02}) {
+		t.Errorf("args of the caller modified: %+v", args)
+	}
+}
+
+func TestBundleGasPrice(t *testing.T) {
+	legacy := func(gasPrice int64, gas uint64) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(gasPrice), Gas: gas})
+	}
+
+	header := &types.Header{}
+	tests := []struct {
+		name string
+		txs  []*types.Transaction
+		want int64
+	}{
+		{"single tx", []*types.Transaction{legacy(3, 21000)}, 3},
+		{"gasless tx sponsored", []*types.Transaction{legacy(0, 21000), legacy(6, 21000)}, 3},
+		{"weighted by gas", []*types.Transaction{legacy(1, 30000), legacy(5, 10000)}, 2},
+		{"no gas", []*types.Transaction{legacy(5, 0)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bundleGasPrice(tt.txs, header); got.Int64() != tt.want {
+				t.Errorf("bundleGasPrice() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package txsender
+
+import (
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+type SendOptions struct {
+	Builders []builder.Builder
+	Profile  string
+}
+
+func (o *SendOptions) ApplyOptions(options ...SendOption) {
+	for _, opt := range options {
+		opt(o)
+	}
+}
+
+type SendOption func(*SendOptions)
+
+// WithBuilders sends to the given builders instead of the ones the sender was created with.
+func WithBuilders(builders []builder.Builder) SendOption {
+	return func(o *SendOptions) {
+		o.Builders = builders
+	}
+}
+
+// WithProfile sends with the named profile of Config.Profiles.
+func WithProfile(name string) SendOption {
+	return func(o *SendOptions) {
+		o.Profile = name
+	}
+}
+
+func (s *privateTxSender) sendOptions(options ...SendOption) *SendOptions {
+	opt := &SendOptions{Builders: s.loadBuilders()}
+	opt.ApplyOptions(options...)
+	return opt
+}
@@ -0,0 +1,181 @@
+package txsender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/router"
+)
+
+// Profile bundles the submission settings of a call site, zero fields keep the sender's behaviour
+// so the zero Profile is the default one.
+type Profile struct {
+	Name             string
+	BundleLifeNumber uint64          // overrides Config.BundleLifeNumber
+	Builders         []builder.Brand // restricts the builders, empty means all
+	Dispatch         router.Dispatch // overrides the dispatch policy of the route
+	Retries          int             // resends when all builders failed
+	RetryInterval    Duration
+	// Fallback broadcasts the tx publicly through the chain node when all builders still failed,
+	// it gives up the privacy of the tx
+	Fallback bool
+	// Simulate runs the bundle on the builders implementing builder.BundleSimulator before sending,
+	// the tx is rejected if the simulation fails
+	Simulate bool
+}
+
+var (
+	ErrUnknownProfile   = errors.New("unknown profile")
+	ErrSimulationFailed = errors.New("bundle simulation failed")
+)
+
+// SimulationError names the tx a builder reported failing in the simulation, it matches
+// ErrSimulationFailed with errors.Is.
+type SimulationError struct {
+	Brand  string
+	TxHash common.Hash
+	Reason string
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("%s: builder %s, tx %s, %s", ErrSimulationFailed, e.Brand, e.TxHash, e.Reason)
+}
+
+func (e *SimulationError) Unwrap() error {
+	return ErrSimulationFailed
+}
+
+func (s *privateTxSender) profile(name string) (Profile, error) {
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	profile, ok := s.profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+	}
+
+	return profile, nil
+}
+
+func (p *Profile) bundleLifeNumber(cfg Config) uint64 {
+	if p.BundleLifeNumber > 0 {
+		return p.BundleLifeNumber
+	}
+
+	return cfg.BundleLifeNumber
+}
+
+func (p *Profile) filter(builders []builder.Builder) []builder.Builder {
+	if len(p.Builders) == 0 {
+		return builders
+	}
+
+	filtered := make([]builder.Builder, 0, len(builders))
+	for _, b := range builders {
+		for _, brand := range p.Builders {
+			if builder.Brand(b.GetBrand()) == brand {
+				filtered = append(filtered, b)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// callBundleResult is the flashbots style result of eth_callBundle.
+type callBundleResult struct {
+	Results []struct {
+		TxHash string `json:"txHash"`
+		Error  string `json:"error"`
+		Revert string `json:"revert"`
+	} `json:"results"`
+}
+
+// simulate fails if any simulating builder reports an error of a tx not listed in RevertingTxHashes,
+// it passes when no builder simulates.
+func (s *privateTxSender) simulate(ctx context.Context, builders []builder.Builder, args *types.SendBundleArgs) error {
+	reverting := make(map[common.Hash]struct{}, len(args.RevertingTxHashes))
+	for _, hash := range args.RevertingTxHashes {
+		reverting[hash] = struct{}{}
+	}
+
+	callArgs := *args
+	callArgs.MaxBlockNumber = s.latestHeader.Load().Number.Uint64() + 1
+
+	simulated := false
+	for _, b := range builders {
+		simulator, ok := b.(builder.BundleSimulator)
+		if !ok {
+			continue
+		}
+
+		result, err := simulator.CallBundle(ctx, &callArgs)
+		if err != nil {
+			log.Warn("failed to simulate bundle", "builder", b.GetBrand(), "err", err)
+			continue
+		}
+
+		simulated = true
+
+		callResult := callBundleResult{}
+		if err := jsoniter.Unmarshal(result, &callResult); err != nil {
+			continue
+		}
+
+		for _, r := range callResult.Results {
+			if _, ok := reverting[common.HexToHash(r.TxHash)]; r.Error != "" && !ok {
+				return &SimulationError{Brand: b.GetBrand(), TxHash: common.HexToHash(r.TxHash), Reason: strings.TrimSpace(r.Error + " " + r.Revert)}
+			}
+		}
+	}
+
+	if !simulated {
+		log.Warn("no builder simulated the bundle")
+	}
+
+	return nil
+}
+
+// sendWithRetry runs send until it succeeds, the retries of the profile are used up or ctx is done.
+func sendWithRetry(ctx context.Context, profile Profile, send func() (BundleResult, error)) (result BundleResult, err error) {
+	for attempt := 0; ; attempt++ {
+		result, err = send()
+		if err == nil || attempt >= profile.Retries {
+			return
+		}
+
+		log.Warn("send bundle to all builders failed, retry", "attempt", attempt+1, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(time.Duration(profile.RetryInterval)):
+		}
+	}
+}
+
+// fallback broadcasts the tx through the chain node.
+func (s *privateTxSender) fallback(ctx context.Context, tx *types.Transaction) (BundleResult, error) {
+	log.Warn("fall back to public broadcast", "tx_hash", tx.Hash())
+
+	if err := s.client.SendTransaction(ctx, tx); err != nil {
+		log.Error("failed to broadcast tx", "tx_hash", tx.Hash(), "err", err)
+		return BundleResult{}, err
+	}
+
+	return BundleResult{TxHash: tx.Hash(), ID: PublicBrand, Brand: PublicBrand}, nil
+}
+
+// PublicBrand is reported as the brand and id of the builder of txs broadcast publicly by the fallback of a profile.
+const PublicBrand = "public"
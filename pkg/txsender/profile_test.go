@@ -0,0 +1,56 @@
+package txsender
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSendWithRetry(t *testing.T) {
+	errSend := errors.New("send failed")
+
+	tests := []struct {
+		name      string
+		profile   Profile
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"first attempt", Profile{Retries: 2}, 0, 1, false},
+		{"succeeds on retry", Profile{Retries: 2}, 2, 3, false},
+		{"retries used up", Profile{Retries: 1}, 5, 2, true},
+		{"no retry", Profile{}, 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := sendWithRetry(context.Background(), tt.profile, func() (BundleResult, error) {
+				calls++
+				if calls <= tt.failures {
+					return BundleResult{}, errSend
+				}
+				return BundleResult{}, nil
+			})
+
+			if calls != tt.wantCalls || (err != nil) != tt.wantErr {
+				t.Errorf("calls = %d, err = %v, want %d calls, error %v", calls, err, tt.wantCalls, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendWithRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, err := sendWithRetry(ctx, Profile{Retries: 3, RetryInterval: Duration(time.Hour)}, func() (BundleResult, error) {
+		return BundleResult{}, errors.New("send failed")
+	})
+
+	if !errors.Is(err, context.Canceled) || time.Since(start) > time.Second {
+		t.Errorf("err = %v after %v, want context.Canceled at once", err, time.Since(start))
+	}
+}
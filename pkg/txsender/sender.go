@@ -2,6 +2,10 @@ package txsender
 
 import (
 	"context"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,11 +19,44 @@ import (
 	"github.com/tredeske/u/ustrings"
 
 	"github.com/node-real/private-tx-sender/pkg/builder"
+	"github.com/node-real/private-tx-sender/pkg/policy"
+	"github.com/node-real/private-tx-sender/pkg/router"
 	"github.com/node-real/private-tx-sender/pkg/rpc"
+	"github.com/node-real/private-tx-sender/pkg/validator"
 )
 
 type PrivateTxSender interface {
-	SendRawTransaction(ctx context.Context, input hexutil.Bytes, revertible bool) error
+	// SendRawTransaction returns the bundle accepted by the first builder that succeeds,
+	// the bundle ids of the other builders are logged along with the tx hash.
+	SendRawTransaction(ctx context.Context, input hexutil.Bytes, revertible bool, options ...SendOption) (BundleResult, error)
+	// SendBundle, CallBundle and CancelBundle pass a bundle to the builders and wait for all of them,
+	// SendBundle selects and tracks the builders like SendRawTransaction does.
+	SendBundle(ctx context.Context, args *types.SendBundleArgs, options ...SendOption) ([]BuilderResult, error)
+	CallBundle(ctx context.Context, args *types.SendBundleArgs, options ...SendOption) ([]BuilderResult, error)
+	CancelBundle(ctx context.Context, bundleIDs map[string]builder.BundleID, options ...SendOption) ([]BuilderResult, error)
+	// BundlePriceFloors returns the latest minimum bundle gas price published by each builder, keyed
+	// by builder.ID.
+	BundlePriceFloors() map[string]*big.Int
+	// TxStatus reports the inclusion status of a tx sent within the last two bundle windows.
+	TxStatus(txHash common.Hash) (TxStatus, bool)
+	// SetBuilders swaps the builders used by new sends, sends in progress and tracked bundles keep the old ones.
+	// The old builders implementing io.Closer and not passed again are closed after a grace period.
+	SetBuilders(ctx context.Context, builders []builder.Builder)
+	// RegisterBuilders adds builders which are only sent to when passed with WithBuilders, such as the
+	// builders of the proxy tenants, to the price floor refresh of the sender.
+	RegisterBuilders(ctx context.Context, builders []builder.Builder)
+	// Drain waits for the sends to builders still running, SendRawTransaction returns before them
+	// on the first success.
+	Drain(ctx context.Context) error
+}
+
+// BundleResult records which builder accepted the bundle and the id it returned, ID is the
+// builder.ID of the builder.
+type BundleResult struct {
+	TxHash   common.Hash
+	ID       string
+	Brand    string
+	BundleID builder.BundleID
 }
 
 type Duration time.Duration
@@ -38,14 +75,44 @@ type Config struct {
 	ChainURL         string
 	BlockInterval    Duration
 	BundleLifeNumber uint64
+	SendUnderpriced  bool // send to builders whose bundle price floor is not met instead of skipping them
+	Validators       validator.Config
+	Policy           policy.Config
+	Router           router.Config
+	Profiles         []Profile
 }
 
+const (
+	priceRefreshInterval     = 30 * time.Second
+	validatorRefreshInterval = time.Minute
+)
+
+var (
+	ErrUnderpriced = errors.New("gas price is below the bundle price floor of all builders")
+	ErrNoBuilder   = errors.New("no builder configured for the route of the tx")
+	// ErrNoMevValidator is returned when none of the in-turn validators of the bundle window runs MEV,
+	// the profiles with Fallback broadcast the tx publicly instead
+	ErrNoMevValidator = errors.New("no in-turn validator of the bundle window accepts bundles")
+)
+
+// builderCloseDelay is how long the builders replaced by SetBuilders stay open, longer than the
+// timeout of the shared http client.
+const builderCloseDelay = 10 * time.Second
+
 type privateTxSender struct {
-	cfg            Config
-	bundleLifeTime time.Duration
-	client         *ethclient.Client
-	latestHeader   atomic.Pointer[types.Header]
-	builders       []builder.Builder
+	cfg          Config
+	client       *ethclient.Client
+	latestHeader atomic.Pointer[types.Header]
+	builders     atomic.Pointer[[]builder.Builder]
+	registered   atomic.Pointer[[]builder.Builder] // see RegisterBuilders
+	tracker      *tracker
+	prices       *builder.PriceCache
+	selector     *validator.Selector // nil if no validator mapping is configured
+	policy       *policy.Policy
+	router       *router.Router
+	profiles     map[string]Profile
+	inflight     sync.WaitGroup // the sends to builders still running, a tx returns on the first success
+	newHeads     chan struct{}  // signalled when the latest header moves to a new block
 }
 
 func NewPrivateTxSender(ctx context.Context, cfg Config, builders []builder.Builder) PrivateTxSender {
@@ -56,21 +123,43 @@ func NewPrivateTxSender(ctx context.Context, cfg Config, builders []builder.Buil
 	}
 
 	s := &privateTxSender{
-		cfg:            cfg,
-		bundleLifeTime: time.Duration(cfg.BundleLifeNumber) * time.Duration(cfg.BlockInterval),
-		client:         client,
-		builders:       builders,
+		cfg:      cfg,
+		client:   client,
+		tracker:  newTracker(),
+		prices:   builder.NewPriceCache(),
+		policy:   policy.New(cfg.Policy),
+		router:   router.New(cfg.Router),
+		profiles: make(map[string]Profile, len(cfg.Profiles)),
+		newHeads: make(chan struct{}, 1),
+	}
+
+	for _, profile := range cfg.Profiles {
+		s.profiles[profile.Name] = profile
+	}
+
+	s.builders.Store(&builders)
+
+	if len(cfg.Validators.Mapping) > 0 {
+		s.selector = validator.NewSelector(client.Client(), cfg.Validators)
 	}
 
 	s.storeHeader()
+	// no floor is applied until the first refresh answers, a slow builder does not delay the start
+	go s.prices.Refresh(ctx, builders)
+	s.refreshValidators(ctx)
 
-	go s.refresh(ctx)
+	// the header is refreshed on its own, a slow builder or validator query does not hold it back
+	go every(ctx, 500*time.Millisecond, s.storeHeader)
+	go every(ctx, priceRefreshInterval, func() { s.prices.Refresh(ctx, s.priceBuilders()) })
+	go every(ctx, validatorRefreshInterval, func() { s.refreshValidators(ctx) })
+	go s.trackInclusion(ctx)
 
 	return s
 }
 
-func (s *privateTxSender) refresh(ctx context.Context) {
-	ticker := time.NewTicker(500 * time.Millisecond)
+// every runs fn on each tick of the interval until ctx is done, a slow run only delays its own next tick.
+func every(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -78,11 +167,80 @@ func (s *privateTxSender) refresh(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.storeHeader()
+			fn()
+		}
+	}
+}
+
+func (s *privateTxSender) loadBuilders() []builder.Builder {
+	return *s.builders.Load()
+}
+
+// priceBuilders returns the builders of the sender and the registered ones, whose floors are refreshed.
+func (s *privateTxSender) priceBuilders() []builder.Builder {
+	builders := s.loadBuilders()
+	if registered := s.registered.Load(); registered != nil {
+		builders = append(append([]builder.Builder(nil), builders...), *registered...)
+	}
+
+	return builders
+}
+
+func (s *privateTxSender) RegisterBuilders(ctx context.Context, builders []builder.Builder) {
+	for {
+		prev := s.registered.Load()
+		registered := builders
+		if prev != nil {
+			registered = append(append([]builder.Builder(nil), *prev...), builders...)
+		}
+
+		if s.registered.CompareAndSwap(prev, &registered) {
+			break
+		}
+	}
+
+	go s.prices.Refresh(ctx, builders)
+}
+
+func (s *privateTxSender) SetBuilders(ctx context.Context, builders []builder.Builder) {
+	old := s.builders.Swap(&builders)
+	s.prices.Retain(s.priceBuilders())
+	go s.prices.Refresh(ctx, builders)
+
+	if old != nil {
+		// the sends in progress get a grace period before the connections of the old builders are closed
+		time.AfterFunc(builderCloseDelay, func() { closeBuilders(*old, builders) })
+	}
+}
+
+// closeBuilders closes the builders holding connections which are not kept in current.
+func closeBuilders(old, current []builder.Builder) {
+	kept := make(map[builder.Builder]struct{}, len(current))
+	for _, b := range current {
+		kept[b] = struct{}{}
+	}
+
+	for _, b := range old {
+		closer, ok := b.(io.Closer)
+		if _, keep := kept[b]; keep || !ok {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			log.Warn("failed to close builder", "builder", b.GetBrand(), "err", err)
 		}
 	}
 }
 
+func (s *privateTxSender) refreshValidators(ctx context.Context) {
+	latestHeader := s.latestHeader.Load()
+	if s.selector == nil || latestHeader == nil {
+		return
+	}
+
+	_ = s.selector.Refresh(ctx, latestHeader)
+}
+
 func (s *privateTxSender) storeHeader() {
 	header, err := s.client.HeaderByNumber(context.Background(), nil)
 	if err != nil {
@@ -90,52 +248,222 @@ func (s *privateTxSender) storeHeader() {
 		return
 	}
 
-	s.latestHeader.Store(header)
+	prev := s.latestHeader.Swap(header)
+	if prev != nil && header.Number.Cmp(prev.Number) <= 0 {
+		return
+	}
+
+	select {
+	case s.newHeads <- struct{}{}:
+	default:
+	}
 }
 
-func (s *privateTxSender) SendRawTransaction(_ context.Context, input hexutil.Bytes, revertible bool) error {
+func (s *privateTxSender) SendRawTransaction(ctx context.Context, input hexutil.Bytes, revertible bool, options ...SendOption) (BundleResult, error) {
+	opt := s.sendOptions(options...)
+	profile, err := s.profile(opt.Profile)
+	if err != nil {
+		log.Error("failed to load profile", "err", err)
+		return BundleResult{}, err
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		log.Error("failed to unmarshal tx", "err", err)
+		return BundleResult{}, err
+	}
+
+	from, err := s.evaluatePolicy(tx)
+	if err != nil {
+		log.Warn("tx rejected by policy", "tx_hash", tx.Hash(), "err", err)
+		return BundleResult{}, err
+	}
+
+	bundleLifeNumber := profile.bundleLifeNumber(s.cfg)
+	bundleLifeTime := time.Duration(bundleLifeNumber) * time.Duration(s.cfg.BlockInterval)
+
 	latestHeader := s.latestHeader.Load()
 	minTimestamp := uint64(time.Unix(int64(latestHeader.Time), 0).Add(time.Duration(s.cfg.BlockInterval)).Unix())
-	maxTimestamp := uint64(time.Unix(int64(latestHeader.Time), 0).Add(s.bundleLifeTime).Unix())
+	maxTimestamp := uint64(time.Unix(int64(latestHeader.Time), 0).Add(bundleLifeTime).Unix())
 	sendBundlerArgs := &types.SendBundleArgs{
 		Txs:            []hexutil.Bytes{input},
-		MaxBlockNumber: latestHeader.Number.Uint64() + s.cfg.BundleLifeNumber,
+		MaxBlockNumber: latestHeader.Number.Uint64() + bundleLifeNumber,
 		MinTimestamp:   &minTimestamp,
 		MaxTimestamp:   &maxTimestamp,
 	}
 
 	if revertible {
-		tx := new(types.Transaction)
-		if err := tx.UnmarshalBinary(input); err != nil {
-			log.Error("failed to unmarshal tx", "err", err)
-			return err
-		}
-
 		sendBundlerArgs.RevertingTxHashes = []common.Hash{tx.Hash()}
 	}
 
-	sendTasks := make([]func() (common.Hash, error), len(s.builders))
+	route, builders, err := s.selectBuilders(tx, from, effectiveGasPrice(tx, latestHeader), profile, opt.Builders, sendBundlerArgs, latestHeader)
+	if errors.Is(err, ErrNoMevValidator) && profile.Fallback {
+		return s.fallback(ctx, tx)
+	}
+
+	if err != nil {
+		return BundleResult{}, err
+	}
+
+	if profile.Simulate {
+		if err := s.simulate(ctx, builders, sendBundlerArgs); err != nil {
+			log.Warn("tx rejected by simulation", "tx_hash", tx.Hash(), "err", err)
+			return BundleResult{}, err
+		}
+	}
+
+	s.tracker.add(tx.Hash(), sendBundlerArgs, latestHeader.Number.Uint64()+1)
 
-	for idx, builder := range s.builders {
-		builder := builder
+	sendTasks := make([]func() (BundleResult, error), len(builders))
 
-		sendTasks[idx] = func() (common.Hash, error) {
-			err := builder.SendBundle(context.Background(), sendBundlerArgs, s.cfg.BundleLifeNumber)
+	for idx, b := range builders {
+		b, id := b, builder.ID(b)
+
+		sendTasks[idx] = func() (BundleResult, error) {
+			defer s.inflight.Done()
+
+			bundleID, err := b.SendBundle(context.Background(), sendBundlerArgs, bundleLifeNumber)
 			if err != nil {
-				log.Error("send bundle to builder failed", "builder", builder.GetBrand(), "err", err.Error())
-			} else {
-				log.Info("send bundle to builder success", "builder", builder.GetBrand())
+				log.Error("send bundle to builder failed", "builder", id, "tx_hash", tx.Hash(), "err", err.Error())
+				return BundleResult{}, err
 			}
 
-			return common.Hash{}, err
+			log.Info("send bundle to builder success", "builder", id, "tx_hash", tx.Hash(), "bundle_id", bundleID)
+
+			result := BundleResult{TxHash: tx.Hash(), ID: id, Brand: b.GetBrand(), BundleID: bundleID}
+			s.tracker.addBundle(b, result)
+			return result, nil
+		}
+	}
+
+	dispatch := route.Dispatch
+	if profile.Dispatch != "" {
+		dispatch = profile.Dispatch
+	}
+
+	run := RunForOnlyOneSucceed[BundleResult]
+	if dispatch == router.DispatchAll {
+		run = RunForAll[BundleResult]
+	}
+
+	result, err := sendWithRetry(ctx, profile, func() (BundleResult, error) {
+		s.inflight.Add(len(sendTasks))
+		return run(sendTasks...)
+	})
+	if err != nil && profile.Fallback {
+		result, err = s.fallback(ctx, tx)
+	}
+
+	if err != nil {
+		s.tracker.remove(tx.Hash())
+		return BundleResult{}, err
+	}
+
+	return result, nil
+}
+
+// selectBuilders picks the builders of a bundle by the route of its lead tx, the validators of the
+// bundle window and the bundle price floors met by gasPrice.
+func (s *privateTxSender) selectBuilders(lead *types.Transaction, from common.Address, gasPrice *big.Int, profile Profile,
+	candidates []builder.Builder, args *types.SendBundleArgs, latestHeader *types.Header) (router.Route, []builder.Builder, error) {
+	route := s.router.Route(lead, from, profile.filter(candidates))
+	if len(route.Builders) == 0 {
+		log.Error("no builder for tx", "tx_hash", lead.Hash(), "rule", route.Rule, "profile", profile.Name)
+		return route, nil, ErrNoBuilder
+	}
+
+	builders := route.Builders
+	if s.selector != nil {
+		builders = s.selector.Select(builders, latestHeader.Number.Uint64()+1, args.MaxBlockNumber)
+		if len(builders) == 0 {
+			log.Warn("no in-turn validator runs mev", "tx_hash", lead.Hash(), "max_block_number", args.MaxBlockNumber)
+			return route, nil, ErrNoMevValidator
 		}
 	}
 
-	if _, err := RunForOnlyOneSucceed(sendTasks...); err != nil {
-		return err
+	builders = s.pricedBuilders(builders, lead.Hash(), gasPrice)
+	if len(builders) == 0 {
+		log.Error("tx is underpriced for all builders", "tx_hash", lead.Hash(), "gas_price", gasPrice)
+		return route, nil, ErrUnderpriced
 	}
 
-	return nil
+	return route, builders, nil
+}
+
+func (s *privateTxSender) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
+// evaluatePolicy returns the sender of the tx if the tx complies with the policy.
+func (s *privateTxSender) evaluatePolicy(tx *types.Transaction) (common.Address, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		log.Error("failed to recover tx sender", "tx_hash", tx.Hash(), "err", err)
+		return common.Address{}, err
+	}
+
+	return from, s.policy.Evaluate(tx, from)
+}
+
+func (s *privateTxSender) BundlePriceFloors() map[string]*big.Int {
+	return s.prices.Floors()
+}
+
+// pricedBuilders filters out the builders whose bundle price floor the gas price does not meet,
+// they are kept with a warning when SendUnderpriced is set.
+func (s *privateTxSender) pricedBuilders(candidates []builder.Builder, txHash common.Hash, gasPrice *big.Int) []builder.Builder {
+	builders := make([]builder.Builder, 0, len(candidates))
+	for _, b := range candidates {
+		floor, ok := s.prices.Floor(b)
+		if ok && gasPrice.Cmp(floor) < 0 {
+			log.Warn("tx gas price below bundle price floor", "builder", b.GetBrand(), "tx_hash", txHash,
+				"gas_price", gasPrice, "floor", floor)
+
+			if !s.cfg.SendUnderpriced {
+				continue
+			}
+		}
+
+		builders = append(builders, b)
+	}
+
+	return builders
+}
+
+func effectiveGasPrice(tx *types.Transaction, header *types.Header) *big.Int {
+	if header.BaseFee == nil {
+		return tx.GasPrice()
+	}
+
+	return new(big.Int).Add(header.BaseFee, tx.EffectiveGasTipValue(header.BaseFee))
+}
+
+// bundleGasPrice is the gas price of the bundle weighted by the gas limit of its txs, so that a
+// gasless tx sponsored by another tx of the bundle does not fail the price floors on its own.
+func bundleGasPrice(txs []*types.Transaction, header *types.Header) *big.Int {
+	fees, gas := new(big.Int), new(big.Int)
+	for _, tx := range txs {
+		txGas := new(big.Int).SetUint64(tx.Gas())
+		fees.Add(fees, txGas.Mul(txGas, effectiveGasPrice(tx, header)))
+		gas.Add(gas, new(big.Int).SetUint64(tx.Gas()))
+	}
+
+	if gas.Sign() == 0 {
+		return gas
+	}
+
+	return fees.Div(fees, gas)
 }
 
 // RunForOnlyOneSucceed returns in two conditions:
@@ -170,6 +498,39 @@ func RunForOnlyOneSucceed[T any](tasks ...func() (T, error)) (t T, err error) {
 	return
 }
 
+// RunForAll waits for all tasks and returns the result of the first succeeded one,
+// an error is returned only if all tasks failed.
+func RunForAll[T any](tasks ...func() (T, error)) (t T, err error) {
+	respChan := make(chan batchResp[T], len(tasks))
+	for _, task := range tasks {
+		task := task
+
+		go func() {
+			var resp batchResp[T]
+			resp.t, resp.err = task()
+			respChan <- resp
+		}()
+	}
+
+	succeed := false
+	for range tasks {
+		resp := <-respChan
+		if resp.err != nil {
+			err = multierror.Append(err, resp.err)
+			continue
+		}
+
+		if !succeed {
+			t, succeed = resp.t, true
+		}
+	}
+
+	if succeed {
+		return t, nil
+	}
+	return
+}
+
 type batchResp[T any] struct {
 	t   T
 	err error
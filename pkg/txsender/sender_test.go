@@ -0,0 +1,40 @@
+package txsender
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+type stubPricer struct {
+	stubBuilder
+	price int64
+}
+
+func (b *stubPricer) BundlePrice(context.Context) (*big.Int, error) {
+	return big.NewInt(b.price), nil
+}
+
+func TestRegisterBuilders(t *testing.T) {
+	own := &stubPricer{stubBuilder{"nodereal"}, 1}
+	tenant := &stubPricer{stubBuilder{"blockrazor"}, 2}
+	replaced := []builder.Builder{own}
+
+	s := &privateTxSender{prices: builder.NewPriceCache()}
+	s.builders.Store(&replaced)
+	s.RegisterBuilders(context.Background(), []builder.Builder{tenant})
+	s.prices.Refresh(context.Background(), s.priceBuilders())
+
+	// the floors of the registered builders survive a swap of the builders of the sender
+	s.SetBuilders(context.Background(), []builder.Builder{&stubPricer{stubBuilder{"titan"}, 3}})
+
+	if _, ok := s.prices.Floor(own); ok {
+		t.Error("floor of the replaced builder kept")
+	}
+
+	if floor, ok := s.prices.Floor(tenant); !ok || floor.Int64() != 2 {
+		t.Errorf("floor of the registered builder = %v, %v, want 2", floor, ok)
+	}
+}
@@ -0,0 +1,299 @@
+package txsender
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+// TxStatus is the inclusion status of a privately sent tx, Builders holds the latest bundle status
+// reported by each builder implementing builder.BundleStatusQuerier, keyed by builder.ID. None of the
+// builders of the package implements it, the state then follows the receipt alone.
+type TxStatus struct {
+	TxHash      common.Hash
+	State       builder.BundleState
+	BlockNumber uint64
+	Reason      string
+	Bundles     []BundleResult
+	Builders    map[string]builder.BundleStatus
+}
+
+type trackedBundle struct {
+	builder  builder.Builder
+	bundleID builder.BundleID
+}
+
+type trackedTx struct {
+	maxBlockNumber uint64
+	args           *types.SendBundleArgs
+	targetBlock    uint64 // the latest block the bundles were sent for
+	bundles        []trackedBundle
+	status         TxStatus
+	settled        bool // included or expired, the receipt is not polled any more
+}
+
+type tracker struct {
+	mu  sync.Mutex
+	txs map[common.Hash]*trackedTx
+}
+
+func newTracker() *tracker {
+	return &tracker{txs: make(map[common.Hash]*trackedTx)}
+}
+
+func (t *tracker) add(txHash common.Hash, args *types.SendBundleArgs, targetBlock uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.txs[txHash] = &trackedTx{
+		maxBlockNumber: args.MaxBlockNumber,
+		args:           args,
+		targetBlock:    targetBlock,
+		status: TxStatus{
+			TxHash:   txHash,
+			State:    builder.BundlePending,
+			Builders: make(map[string]builder.BundleStatus),
+		},
+	}
+}
+
+func (t *tracker) remove(txHash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.txs, txHash)
+}
+
+func (t *tracker) addBundle(b builder.Builder, result BundleResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx, ok := t.txs[result.TxHash]
+	if !ok {
+		return
+	}
+
+	tx.bundles = append(tx.bundles, trackedBundle{builder: b, bundleID: result.BundleID})
+	tx.status.Bundles = append(tx.status.Bundles, result)
+}
+
+func (t *tracker) get(txHash common.Hash) (TxStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx, ok := t.txs[txHash]
+	if !ok {
+		return TxStatus{}, false
+	}
+
+	status := tx.status
+	status.Bundles = append([]BundleResult(nil), tx.status.Bundles...)
+	status.Builders = make(map[string]builder.BundleStatus, len(tx.status.Builders))
+	for id, bs := range tx.status.Builders {
+		status.Builders[id] = bs
+	}
+
+	return status, true
+}
+
+// pending returns a snapshot of the txs which are still waiting for inclusion, a failed simulation
+// reported by the builders does not stop the receipt polling.
+func (t *tracker) pending() map[common.Hash]trackedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	txs := make(map[common.Hash]trackedTx)
+	for hash, tx := range t.txs {
+		if !tx.settled {
+			txs[hash] = trackedTx{
+				maxBlockNumber: tx.maxBlockNumber,
+				args:           tx.args,
+				targetBlock:    tx.targetBlock,
+				bundles:        append([]trackedBundle(nil), tx.bundles...),
+			}
+		}
+	}
+
+	return txs
+}
+
+func (t *tracker) update(txHash common.Hash, fn func(status *TxStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tx, ok := t.txs[txHash]; ok {
+		fn(&tx.status)
+	}
+}
+
+// settle updates the status for the last time, the tx is not polled any more.
+func (t *tracker) settle(txHash common.Hash, fn func(status *TxStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tx, ok := t.txs[txHash]; ok {
+		tx.settled = true
+		fn(&tx.status)
+	}
+}
+
+// retarget moves the target block of the tx forward, it returns false if the tx already targets the block.
+func (t *tracker) retarget(txHash common.Hash, blockNumber uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx, ok := t.txs[txHash]
+	if !ok || tx.targetBlock >= blockNumber {
+		return false
+	}
+
+	tx.targetBlock = blockNumber
+	return true
+}
+
+// prune drops the txs whose bundle window closed more than retain blocks ago.
+func (t *tracker) prune(blockNumber, retain uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for hash, tx := range t.txs {
+		if tx.maxBlockNumber+retain < blockNumber {
+			delete(t.txs, hash)
+		}
+	}
+}
+
+func (s *privateTxSender) TxStatus(txHash common.Hash) (TxStatus, bool) {
+	return s.tracker.get(txHash)
+}
+
+// trackInclusion checks the pending txs on every new head, so the single block bundles are resent
+// for the next block as soon as the current one is sealed.
+func (s *privateTxSender) trackInclusion(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.newHeads:
+			latestHeader := s.latestHeader.Load()
+			if latestHeader == nil {
+				continue
+			}
+
+			blockNumber := latestHeader.Number.Uint64()
+			for txHash, tx := range s.tracker.pending() {
+				s.checkInclusion(ctx, txHash, tx, blockNumber)
+			}
+
+			s.tracker.prune(blockNumber, s.cfg.BundleLifeNumber)
+		}
+	}
+}
+
+func (s *privateTxSender) checkInclusion(ctx context.Context, txHash common.Hash, tx trackedTx, blockNumber uint64) {
+	receipt, err := s.client.TransactionReceipt(ctx, txHash)
+	if err == nil {
+		s.tracker.settle(txHash, func(status *TxStatus) {
+			status.State = builder.BundleIncluded
+			status.BlockNumber = receipt.BlockNumber.Uint64()
+			status.Reason = ""
+			if receipt.Status == 0 {
+				status.Reason = "reverted"
+			}
+		})
+
+		log.Info("private tx included", "tx_hash", txHash, "block", receipt.BlockNumber)
+		return
+	}
+
+	if !errors.Is(err, ethereum.NotFound) {
+		log.Error("failed to get tx receipt", "tx_hash", txHash, "err", err)
+		return
+	}
+
+	if blockNumber > tx.maxBlockNumber {
+		s.tracker.settle(txHash, func(status *TxStatus) {
+			// the failed simulation explains why the tx was not included
+			if status.State != builder.BundleSimulatedFailed {
+				status.State = builder.BundleExpired
+				status.Reason = "bundle window passed without inclusion"
+			}
+		})
+
+		log.Warn("private tx expired", "tx_hash", txHash, "max_block", tx.maxBlockNumber)
+		return
+	}
+
+	s.resendBundles(ctx, txHash, tx, blockNumber+1)
+	s.queryBundleStatus(ctx, txHash, tx)
+}
+
+// resendBundles sends the bundle again for the next block to the builders which only accept a
+// bundle for a single block.
+func (s *privateTxSender) resendBundles(ctx context.Context, txHash common.Hash, tx trackedTx, nextBlock uint64) {
+	if nextBlock > tx.maxBlockNumber || !s.tracker.retarget(txHash, nextBlock) {
+		return
+	}
+
+	for _, b := range tx.bundles {
+		resender, ok := b.builder.(builder.BundleResender)
+		if !ok {
+			continue
+		}
+
+		err := resender.ResendBundle(ctx, b.bundleID, tx.args, nextBlock)
+		if errors.Is(err, builder.ErrUnknownBundle) {
+			// cancelled
+			continue
+		}
+
+		if err != nil {
+			log.Warn("failed to resend bundle", "builder", b.builder.GetBrand(), "tx_hash", txHash, "block", nextBlock, "err", err)
+		}
+	}
+}
+
+// queryBundleStatus asks the builders supporting status lookups about the bundle, the tx is
+// marked as failed only when every builder which accepted the bundle supports lookups and reports a
+// failed simulation. A builder without lookups may still include the bundle, the failures reported
+// by the others are kept in Builders and the tx stays pending.
+func (s *privateTxSender) queryBundleStatus(ctx context.Context, txHash common.Hash, tx trackedTx) {
+	failed, reason := 0, ""
+	for _, b := range tx.bundles {
+		querier, ok := b.builder.(builder.BundleStatusQuerier)
+		if !ok {
+			continue
+		}
+
+		bundleStatus, err := querier.BundleStatus(ctx, b.bundleID)
+		if err != nil {
+			continue
+		}
+
+		id := builder.ID(b.builder)
+		s.tracker.update(txHash, func(status *TxStatus) {
+			status.Builders[id] = *bundleStatus
+		})
+
+		if bundleStatus.State == builder.BundleSimulatedFailed {
+			log.Warn("bundle simulation failed", "builder", id, "tx_hash", txHash, "reason", bundleStatus.Reason)
+
+			failed++
+			reason = bundleStatus.Reason
+		}
+	}
+
+	if len(tx.bundles) > 0 && failed == len(tx.bundles) {
+		s.tracker.update(txHash, func(status *TxStatus) {
+			status.State = builder.BundleSimulatedFailed
+			status.Reason = reason
+		})
+	}
+}
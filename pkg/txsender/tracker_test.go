@@ -0,0 +1,75 @@
+package txsender
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+type stubBuilder struct {
+	brand string
+}
+
+func (b *stubBuilder) SendBundle(context.Context, *types.SendBundleArgs, uint64) (builder.BundleID, error) {
+	return "", nil
+}
+
+func (b *stubBuilder) GetBrand() string {
+	return b.brand
+}
+
+type stubQuerier struct {
+	stubBuilder
+	state builder.BundleState
+}
+
+func (b *stubQuerier) BundleStatus(context.Context, builder.BundleID) (*builder.BundleStatus, error) {
+	return &builder.BundleStatus{State: b.state, Reason: "reverted in simulation"}, nil
+}
+
+func TestQueryBundleStatus(t *testing.T) {
+	failed := &stubQuerier{stubBuilder{"a"}, builder.BundleSimulatedFailed}
+	pending := &stubQuerier{stubBuilder{"b"}, builder.BundlePending}
+	plain := &stubBuilder{"c"}
+
+	tests := []struct {
+		name     string
+		builders []builder.Builder
+		want     builder.BundleState
+	}{
+		{"all queriers failed", []builder.Builder{failed}, builder.BundleSimulatedFailed},
+		{"builder without status may still include", []builder.Builder{failed, plain}, builder.BundlePending},
+		{"one querier pending", []builder.Builder{failed, pending}, builder.BundlePending},
+		{"no querier", []builder.Builder{plain}, builder.BundlePending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &privateTxSender{tracker: newTracker()}
+			txHash := common.HexToHash("0x01")
+			s.tracker.add(txHash, &types.SendBundleArgs{MaxBlockNumber: 10}, 1)
+			for _, b := range tt.builders {
+				s.tracker.addBundle(b, BundleResult{TxHash: txHash, Brand: b.GetBrand(), BundleID: "id"})
+			}
+
+			s.queryBundleStatus(context.Background(), txHash, s.tracker.pending()[txHash])
+
+			status, _ := s.tracker.get(txHash)
+			if status.State != tt.want {
+				t.Errorf("state = %s, want %s", status.State, tt.want)
+			}
+
+			if bs, ok := status.Builders[builder.ID(failed)]; ok != (tt.builders[0] == failed) || (ok && bs.State != builder.BundleSimulatedFailed) {
+				t.Errorf("builders = %v, want the failure of %s recorded", status.Builders, builder.ID(failed))
+			}
+
+			if _, ok := s.tracker.pending()[txHash]; !ok {
+				t.Error("tx is not polled any more before inclusion or expiry")
+			}
+		})
+	}
+}
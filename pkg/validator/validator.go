@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+const (
+	GetValidatorsMethod = "parlia_getValidators"
+	GetTurnLengthMethod = "parlia_getTurnLength"
+)
+
+// Mapping lists the builder brands a validator accepts bids from, an empty list means the validator does not run MEV.
+// mev_params does not expose the builders of a validator, so the mapping has to be configured.
+type Mapping struct {
+	Address  common.Address
+	Builders []builder.Brand
+}
+
+type Config struct {
+	Mapping []Mapping
+}
+
+// Selector predicts the in-turn validators of the upcoming blocks and picks the builders connected to them.
+type Selector struct {
+	client   *ethrpc.Client
+	builders map[common.Address]map[builder.Brand]struct{}
+
+	mu         sync.RWMutex
+	validators []common.Address // sorted ascending, as parlia orders them
+	turnLength uint64
+}
+
+func NewSelector(client *ethrpc.Client, cfg Config) *Selector {
+	s := &Selector{
+		client:     client,
+		builders:   make(map[common.Address]map[builder.Brand]struct{}, len(cfg.Mapping)),
+		turnLength: 1,
+	}
+
+	for _, m := range cfg.Mapping {
+		brands := make(map[builder.Brand]struct{}, len(m.Builders))
+		for _, brand := range m.Builders {
+			brands[brand] = struct{}{}
+		}
+
+		s.builders[m.Address] = brands
+	}
+
+	return s
+}
+
+// Refresh reloads the validator set and turn length at the given header.
+func (s *Selector) Refresh(ctx context.Context, header *types.Header) error {
+	number := ethrpc.BlockNumber(header.Number.Int64())
+
+	var validators []common.Address
+	if err := s.client.CallContext(ctx, &validators, GetValidatorsMethod, &number); err != nil {
+		log.Error("failed to get validators", "block", number, "err", err)
+		return err
+	}
+
+	// nodes before the Bohr upgrade have no turn length, a validator produces one block per turn
+	var turnLength uint8
+	if err := s.client.CallContext(ctx, &turnLength, GetTurnLengthMethod, &number); err != nil || turnLength == 0 {
+		turnLength = 1
+	}
+
+	s.mu.Lock()
+	s.validators = validators
+	s.turnLength = uint64(turnLength)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// InturnValidators returns the in-turn validators of the blocks in [from, to].
+func (s *Selector) InturnValidators(from, to uint64) []common.Address {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.validators) == 0 {
+		return nil
+	}
+
+	inturns := make([]common.Address, 0)
+	seen := make(map[common.Address]struct{})
+	for number := from; number <= to; number++ {
+		validator := s.validators[number/s.turnLength%uint64(len(s.validators))]
+		if _, ok := seen[validator]; ok {
+			continue
+		}
+
+		seen[validator] = struct{}{}
+		inturns = append(inturns, validator)
+	}
+
+	return inturns
+}
+
+// Select returns the builders accepted by the in-turn validators of the blocks in [from, to].
+// All builders are returned if the validator set is unknown, any of the validators is not mapped
+// or none of the builders is connected to them. None is returned when all of the validators are
+// mapped to no builder, as no bundle can land in the window.
+func (s *Selector) Select(builders []builder.Builder, from, to uint64) []builder.Builder {
+	validators := s.InturnValidators(from, to)
+	if len(validators) == 0 {
+		return builders
+	}
+
+	accepted := make(map[builder.Brand]struct{})
+	for _, validator := range validators {
+		brands, ok := s.builders[validator]
+		if !ok {
+			log.Debug("unknown validator in turn, broadcast to all builders", "validator", validator)
+			return builders
+		}
+
+		for brand := range brands {
+			accepted[brand] = struct{}{}
+		}
+	}
+
+	if len(accepted) == 0 {
+		return nil
+	}
+
+	selected := make([]builder.Builder, 0, len(builders))
+	for _, b := range builders {
+		if _, ok := accepted[builder.Brand(b.GetBrand())]; ok {
+			selected = append(selected, b)
+		}
+	}
+
+	if len(selected) == 0 {
+		return builders
+	}
+
+	return selected
+}
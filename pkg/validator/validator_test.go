@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/node-real/private-tx-sender/pkg/builder"
+)
+
+type stubBuilder struct {
+	brand builder.Brand
+}
+
+func (b stubBuilder) SendBundle(context.Context, *types.SendBundleArgs, uint64) (builder.BundleID, error) {
+	return "", nil
+}
+
+func (b stubBuilder) GetBrand() string {
+	return string(b.brand)
+}
+
+var (
+	validatorA = common.HexToAddress("0x000000000000000000000000000000000000000a")
+	validatorB = common.HexToAddress("0x000000000000000000000000000000000000000b")
+	validatorC = common.HexToAddress("0x000000000000000000000000000000000000000c")
+)
+
+// newSelector serves the validator set and the turn length, a zero turn length answers an error
+// as the nodes before Bohr do.
+func newSelector(t *testing.T, validators []common.Address, turnLength uint8, mapping []Mapping) *Selector {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch {
+		case req.Method == GetValidatorsMethod:
+			resp["result"] = validators
+		case req.Method == GetTurnLengthMethod && turnLength > 0:
+			resp["result"] = turnLength
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := ethrpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+
+	s := NewSelector(client, Config{Mapping: mapping})
+	if err := s.Refresh(context.Background(), &types.Header{Number: big.NewInt(100)}); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestInturnValidators(t *testing.T) {
+	validators := []common.Address{validatorA, validatorB, validatorC}
+
+	tests := []struct {
+		name       string
+		turnLength uint8
+		from, to   uint64
+		want       []common.Address
+	}{
+		{"single block", 0, 3, 3, []common.Address{validatorA}},
+		{"one block per turn", 0, 4, 6, []common.Address{validatorB, validatorC, validatorA}},
+		{"window longer than the set", 0, 0, 10, []common.Address{validatorA, validatorB, validatorC}},
+		{"turn length", 4, 4, 11, []common.Address{validatorB, validatorC}},
+		{"within a turn", 4, 8, 10, []common.Address{validatorC}},
+		{"wraps around", 4, 11, 12, []common.Address{validatorC, validatorA}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSelector(t, validators, tt.turnLength, nil)
+			got := s.InturnValidators(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("InturnValidators() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("InturnValidators() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	builders := []builder.Builder{
+		stubBuilder{builder.Nodereal},
+		stubBuilder{builder.Blockrazor},
+		stubBuilder{builder.Bloxroute},
+	}
+
+	mapping := []Mapping{
+		{Address: validatorA, Builders: []builder.Brand{builder.Nodereal}},
+		{Address: validatorB, Builders: []builder.Brand{builder.Blockrazor, builder.Bloxroute}},
+		{Address: validatorC, Builders: []builder.Brand{builder.Txboost}},
+	}
+
+	tests := []struct {
+		name       string
+		validators []common.Address
+		mapping    []Mapping
+		from, to   uint64
+		want       []builder.Brand
+	}{
+		{"builders of the in-turn validator", []common.Address{validatorA, validatorB}, mapping, 0, 0,
+			[]builder.Brand{builder.Nodereal}},
+		{"union over the window", []common.Address{validatorA, validatorB}, mapping, 0, 1,
+			[]builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}},
+		{"unmapped validator", []common.Address{validatorA, validatorB}, mapping[:1], 0, 1,
+			[]builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}},
+		{"no mapped builder configured", []common.Address{validatorC}, mapping, 0, 0,
+			[]builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}},
+		{"non-mev validators", []common.Address{validatorA, validatorB}, []Mapping{{Address: validatorA}, {Address: validatorB}}, 0, 1, nil},
+		{"non-mev and mev validators", []common.Address{validatorA, validatorB}, []Mapping{{Address: validatorA}, mapping[1]}, 0, 1,
+			[]builder.Brand{builder.Blockrazor, builder.Bloxroute}},
+		{"unknown validator set", nil, mapping, 0, 0,
+			[]builder.Brand{builder.Nodereal, builder.Blockrazor, builder.Bloxroute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSelector(t, tt.validators, 0, tt.mapping)
+			got := s.Select(builders, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Select() = %d builders, want %v", len(got), tt.want)
+			}
+
+			for i, b := range got {
+				if builder.Brand(b.GetBrand()) != tt.want[i] {
+					t.Fatalf("Select()[%d] = %s, want %s", i, b.GetBrand(), tt.want[i])
+				}
+			}
+		})
+	}
+}